@@ -53,12 +53,53 @@ func (app *app) cmdInstallCertAndReset(_ context.Context, args []string) error {
 		return err
 	}
 
+	// close stale sessions on login?
+	if app.config.closeStaleSessions != nil && *app.config.closeStaleSessions {
+		rac.SetCloseStaleSessions(true)
+	}
+
 	// login to idrac and save the sid cookie
-	_, err = rac.Login()
+	loginResp, err := rac.Login()
 	if err != nil {
 		return fmt.Errorf("login error: %w", err)
 	}
 
+	// always logout, even on failure, so sessions don't pile up on the idrac
+	// don't worry about error
+	// an error isn't too concerning as rac may reset before logout actually processes
+	defer func() { _, _ = rac.Logout() }()
+
+	// default credentials in use?
+	if loginResp.DefaultCredentialsInUse {
+		app.stdLogger.Println("WARNING: ********************************************************")
+		app.stdLogger.Println("WARNING: the idrac reports DEFAULT CREDENTIALS (e.g. root/calvin) are in use")
+		app.stdLogger.Println("WARNING: change the password (see --change-default-password)")
+		app.stdLogger.Println("WARNING: ********************************************************")
+
+		if app.config.changeDefaultPassword != nil && *app.config.changeDefaultPassword != "" {
+			err = rac.ChangeOwnPassword(context.Background(), *app.config.changeDefaultPassword)
+			if err != nil {
+				return fmt.Errorf("failed to change default password (%w)", err)
+			}
+			app.stdLogger.Println("default password changed and verified")
+		}
+	}
+	if version, err := rac.FirmwareVersion(context.Background()); err == nil {
+		app.stdLogger.Printf("idrac firmware version: %s", version.Raw)
+	} else {
+		app.stdLogger.Printf("unable to read idrac firmware version (%s)", err)
+	}
+	if loginResp.State != idrac.LoginStateOK && loginResp.State != idrac.LoginStateUnknown {
+		app.stdLogger.Printf("WARNING: idrac reports login state %s", loginResp.State)
+	}
+
+	// preflight: check idrac clock against the cert's validity
+	fixClock := app.config.fixClock != nil && *app.config.fixClock
+	err = app.preflightClock(rac, certPem, fixClock)
+	if err != nil {
+		return fmt.Errorf("clock preflight failed (%w)", err)
+	}
+
 	// execute 3 commands: sslkeyupload, sslcertupload, racreset
 	// sslkeyupload
 	_, err = rac.Exec("sslkeyupload", []string{"-t", "1", "-f", string(keyPem)})
@@ -81,10 +122,5 @@ func (app *app) cmdInstallCertAndReset(_ context.Context, args []string) error {
 	}
 	app.stdLogger.Println("racreset: idrac reset")
 
-	// logout of the idrac
-	_, _ = rac.Logout()
-	// don't worry about error
-	// an error isn't too concerning as rac may reset before logout actually processes
-
 	return nil
 }
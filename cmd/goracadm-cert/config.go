@@ -29,7 +29,10 @@ type config struct {
 	username *string
 	password *string
 	keyCertPemCfg
-	insecure *bool
+	insecure              *bool
+	closeStaleSessions    *bool
+	changeDefaultPassword *string
+	fixClock              *bool
 }
 
 // getConfig returns the app's configuration from either command line args,
@@ -49,6 +52,9 @@ func (app *app) getConfig() error {
 	cfg.keyPem = rootFlags.StringLong("keypem", "", "string of the rsa-2048 key in pem format")
 	cfg.certPem = rootFlags.StringLong("certpem", "", "string of the certificate in pem format")
 	cfg.insecure = rootFlags.BoolLong("insecure", "disable https certificate validation (DANGEROUS)")
+	cfg.changeDefaultPassword = rootFlags.StringLong("change-default-password", "", "if the idrac reports default credentials are in use, change the login user's password to this right after login")
+	cfg.fixClock = rootFlags.BoolLong("fix-clock", "if the idrac's clock would make the new cert appear not yet valid (or is off by more than 5 minutes), set it to this machine's time")
+	cfg.closeStaleSessions = rootFlags.BoolLong("close-stale-sessions", "if login is refused (e.g. session limit), close this user's sessions from this host and retry")
 
 	rootCmd := &ff.Command{
 		Name:      "goracadm-cert",
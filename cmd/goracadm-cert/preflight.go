@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"time"
+)
+
+// maxClockSkew is the idrac clock skew that is warned about even if the
+// certificate would still appear valid
+const maxClockSkew = 5 * time.Minute
+
+// clockRac is the subset of idrac used by the clock preflight
+type clockRac interface {
+	ClockSkew(ctx context.Context) (time.Duration, error)
+	SetRACTime(ctx context.Context, t time.Time) error
+}
+
+// preflightClock compares the idrac's clock to the local clock and warns
+// if the skew would make the new certificate appear not yet valid (or is
+// large). If fix is true, the idrac's clock is set to the local time.
+func (app *app) preflightClock(rac clockRac, certPem []byte, fix bool) error {
+	// parse cert for its validity
+	pemBlock, _ := pem.Decode(certPem)
+	if pemBlock == nil {
+		return errors.New("preflight: failed to decode cert pem")
+	}
+	cert, err := x509.ParseCertificate(pemBlock.Bytes)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	skew, err := rac.ClockSkew(ctx)
+	if err != nil {
+		// older idracs may not support getractime -d, not fatal
+		app.stdLogger.Printf("WARNING: preflight: unable to check idrac clock (%s)", err)
+		return nil
+	}
+
+	racNow := time.Now().Add(skew)
+	notYetValid := racNow.Before(cert.NotBefore)
+	largeSkew := skew > maxClockSkew || skew < -maxClockSkew
+
+	if !notYetValid && !largeSkew {
+		app.stdLogger.Printf("preflight: idrac clock ok (skew %s)", skew.Round(time.Second))
+		return nil
+	}
+
+	if notYetValid {
+		app.stdLogger.Printf("WARNING: preflight: idrac time (%s) is before the certificate's not before (%s), the certificate will appear not yet valid",
+			racNow.UTC().Format(time.RFC3339), cert.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if largeSkew {
+		app.stdLogger.Printf("WARNING: preflight: idrac clock is off by %s", skew.Round(time.Second))
+	}
+
+	if !fix {
+		app.stdLogger.Println("WARNING: preflight: use --fix-clock to set the idrac's clock to this machine's time (or configure ntp on the idrac)")
+		return nil
+	}
+
+	err = rac.SetRACTime(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	app.stdLogger.Println("preflight: idrac clock set to local time")
+
+	return nil
+}
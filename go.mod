@@ -10,4 +10,7 @@ replace github.com/gregtwallace/goracadm/pkg/app => /pkg/app
 
 replace github.com/gregtwallace/goracadm/pkg/idrac => /pkg/idrac
 
-require github.com/peterbourgon/ff/v4 v4.0.0-alpha.4
+require (
+	github.com/peterbourgon/ff/v4 v4.0.0-alpha.4
+	gopkg.in/yaml.v3 v3.0.1
+)
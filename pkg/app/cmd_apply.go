@@ -0,0 +1,83 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+)
+
+// errDrift is returned by apply -check when the idrac doesn't match the
+// desired state
+var errDrift = errors.New("idrac settings drifted from desired state")
+
+// cmdApply diffs the desired state file against the idrac's live settings,
+// prints the plan and then applies the differences (unless -plan or -check)
+func cmdApply(rac stateRac, hostname string, args []string) error {
+	// parse command flags (options)
+	stateFilePath := ""
+	planOnly := false
+	check := false
+
+	fs := flag.NewFlagSet("apply", flag.ExitOnError)
+	fs.StringVar(&stateFilePath, "f", "", "desired state file (yaml or json) (required)")
+	fs.BoolVar(&planOnly, "plan", false, "print the plan but don't apply it")
+	fs.BoolVar(&check, "check", false, "print the plan and exit non-zero if there is drift")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if len(fs.Args()) > 0 {
+		return fmt.Errorf("apply: unexpected args %v", fs.Args())
+	}
+	if stateFilePath == "" {
+		return errors.New("apply: desired state file (-f) must be specified")
+	}
+
+	// load and resolve desired state for this host
+	state, err := loadStateFile(stateFilePath)
+	if err != nil {
+		return err
+	}
+	desired, err := state.settingsFor(hostname)
+	if err != nil {
+		return err
+	}
+
+	// plan
+	ctx := context.Background()
+	p := makePlan(ctx, rac, desired)
+	p.print(hostname)
+
+	changes, writeOnly, errs := p.counts()
+	if errs > 0 {
+		return fmt.Errorf("apply: plan has %d errors", errs)
+	}
+
+	if check {
+		if changes > 0 {
+			return errDrift
+		}
+		return nil
+	}
+	if planOnly || changes+writeOnly == 0 {
+		return nil
+	}
+
+	// apply
+	results, err := p.apply(ctx, rac)
+	for _, result := range results {
+		log.Printf("apply: %s set to %s", result.Key, p.displayValue(result.Key, result.Value))
+		if result.JobRequired {
+			log.Printf("apply: %s is pending, a configuration job is required (reboot required: %t)", result.Key, result.RebootRequired)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("apply: %d settings applied", len(results))
+	return nil
+}
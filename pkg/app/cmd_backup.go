@@ -0,0 +1,221 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gregtwallace/goracadm/pkg/idrac"
+)
+
+// backupRac is the subset of idrac used to back up the configuration
+type backupRac interface {
+	Backup(ctx context.Context) (idrac.Backup, error)
+}
+
+// restoreRac is the subset of idrac used to restore a backup
+type restoreRac interface {
+	stateRac
+	WebCertificate(ctx context.Context) (string, error)
+}
+
+// networkKeyPrefixes are the settings that change how the idrac is reached,
+// which restore applies last
+var networkKeyPrefixes = []string{
+	"idrac.ipv4.", "idrac.ipv4static.", "idrac.ipv6.", "idrac.ipv6static.", "idrac.nic.",
+	"cfglannetworking.", "cfgipv6lannetworking.",
+}
+
+// isNetworkKey returns true if the setting key is a network setting
+func isNetworkKey(key string) bool {
+	lowerKey := strings.ToLower(key)
+	for _, prefix := range networkKeyPrefixes {
+		if strings.HasPrefix(lowerKey, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostFileName returns hostname made safe to use in a file name
+func hostFileName(hostname string) string {
+	return strings.NewReplacer(":", "_", "/", "_", "[", "", "]", "").Replace(hostname)
+}
+
+// defaultBackupPath returns the default backup file name for hostname
+func defaultBackupPath(hostname string) string {
+	return fmt.Sprintf("%s-%s.idrac-backup.tar.gz", hostFileName(hostname), time.Now().Format("20060102-150405"))
+}
+
+// writeBackupFile backs up rac to path (the default name if empty) and
+// returns the path written
+func writeBackupFile(ctx context.Context, rac backupRac, hostname, path string) (string, error) {
+	if path == "" {
+		path = defaultBackupPath(hostname)
+	}
+
+	backup, err := rac.Backup(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	err = idrac.WriteBackup(f, backup)
+	if err != nil {
+		return "", err
+	}
+
+	log.Printf("backup: %d settings, %d users, %d write-only settings not included", len(backup.Settings), len(backup.Users), len(backup.Manifest.WriteOnly))
+	return path, f.Close()
+}
+
+// cmdBackup writes a backup of the idrac's configuration to an archive.
+// Usage: backup [-o file]
+func cmdBackup(rac backupRac, hostname string, args []string) error {
+	// parse command flags (options)
+	outFilePath := ""
+
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	fs.StringVar(&outFilePath, "o", "", "backup file (default <hostname>-<time>.idrac-backup.tar.gz)")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if len(fs.Args()) > 0 {
+		return fmt.Errorf("backup: unexpected args %v", fs.Args())
+	}
+
+	path, err := writeBackupFile(context.Background(), rac, hostname, outFilePath)
+	if err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+
+	log.Printf("backup: written to %s", path)
+	return nil
+}
+
+// cmdRestore replays a backup's settings onto the idrac. Like apply, the
+// plan of differences is printed first and only differing settings are
+// written; network settings are written last since they can change how the
+// idrac is reached.
+// Usage: restore -f file [-plan] [-skipnetwork]
+func cmdRestore(rac restoreRac, hostname string, args []string) error {
+	// parse command flags (options)
+	backupPath := ""
+	planOnly := false
+	skipNetwork := false
+
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	fs.StringVar(&backupPath, "f", "", "backup file (required)")
+	fs.BoolVar(&planOnly, "plan", false, "print the plan but don't restore")
+	fs.BoolVar(&skipNetwork, "skipnetwork", false, "don't restore network settings")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if len(fs.Args()) > 0 {
+		return fmt.Errorf("restore: unexpected args %v", fs.Args())
+	}
+	if backupPath == "" {
+		return errors.New("restore: backup file (-f) must be specified")
+	}
+
+	// read backup
+	f, err := os.Open(backupPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	backup, err := idrac.ReadBackup(f)
+	if err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+	log.Printf("restore: backup of %s (service tag %s, firmware %s) from %s", backup.Manifest.Hostname,
+		backup.Manifest.ServiceTag, backup.Manifest.FirmwareVersion, backup.Manifest.CreatedAt.Format(time.RFC3339))
+
+	// split settings, network last
+	settings := stateSettings{Attributes: make(map[string]string), Objects: make(map[string]string)}
+	network := stateSettings{Attributes: make(map[string]string), Objects: make(map[string]string)}
+	for key, value := range backup.Settings {
+		target := &settings
+		if isNetworkKey(key) {
+			if skipNetwork {
+				continue
+			}
+			target = &network
+		}
+
+		if backup.Manifest.Legacy {
+			target.Objects[key] = value
+		} else {
+			target.Attributes[key] = value
+		}
+	}
+
+	// plan
+	ctx := context.Background()
+	p := makePlan(ctx, rac, settings)
+	netPlan := makePlan(ctx, rac, network)
+	p.print(hostname)
+	if len(netPlan) > 0 {
+		fmt.Println("network settings (restored last):")
+		netPlan.print(hostname)
+	}
+
+	// things that can't be restored
+	for _, item := range append(p, netPlan...) {
+		if item.Action == planChange && strings.HasSuffix(strings.ToLower(item.Key), "username") && item.Desired != "" {
+			fmt.Printf("NOTE: user %s will be restored without a password (passwords aren't backed up), set one after restore\n", item.Desired)
+		}
+	}
+	if len(backup.Manifest.WriteOnly) > 0 {
+		fmt.Printf("NOTE: %d write-only settings (e.g. passwords) aren't in the backup and won't be restored\n", len(backup.Manifest.WriteOnly))
+	}
+	if backup.WebCertificate != "" {
+		current, err := rac.WebCertificate(ctx)
+		if err != nil || strings.TrimSpace(current) != strings.TrimSpace(backup.WebCertificate) {
+			fmt.Println("NOTE: the web certificate differs from the backup; it can't be restored (the private key isn't in the backup), reinstall it with goracadm-cert")
+		}
+	}
+
+	changes, writeOnly, errs := p.counts()
+	netChanges, netWriteOnly, netErrs := netPlan.counts()
+	if errs+netErrs > 0 {
+		log.Printf("restore: %d settings can't be restored (see plan), continuing with the rest", errs+netErrs)
+	}
+	if planOnly || changes+writeOnly+netChanges+netWriteOnly == 0 {
+		return nil
+	}
+
+	// restore
+	restored := 0
+	for _, toApply := range []plan{p, netPlan} {
+		results, err := toApply.apply(ctx, rac)
+		for _, result := range results {
+			log.Printf("restore: %s set to %s", result.Key, toApply.displayValue(result.Key, result.Value))
+			if result.JobRequired {
+				log.Printf("restore: %s is pending, a configuration job is required (reboot required: %t)", result.Key, result.RebootRequired)
+			}
+		}
+		restored += len(results)
+		if err != nil {
+			return fmt.Errorf("restore: %w (%d settings restored)", err, restored)
+		}
+	}
+
+	log.Printf("restore: %d settings restored", restored)
+	return nil
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/gregtwallace/goracadm/pkg/idrac"
+)
+
+// inventoryRac is the subset of idrac used to read the hardware inventory
+type inventoryRac interface {
+	HardwareInventory(ctx context.Context) (idrac.HardwareInventory, error)
+}
+
+// cmdInventory reads the hardware inventory and writes it as json or csv
+// (to stdout, or a file if -o is specified). The csv has one row per
+// component property so every property is kept regardless of type.
+func cmdInventory(rac inventoryRac, args []string) error {
+	// parse command flags (options)
+	format := ""
+	outFilePath := ""
+
+	fs := flag.NewFlagSet("inventory", flag.ExitOnError)
+	fs.StringVar(&format, "format", "json", "output format, json or csv")
+	fs.StringVar(&outFilePath, "o", "", "output file (optional, default stdout)")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if len(fs.Args()) > 0 {
+		return fmt.Errorf("inventory: unexpected args %v", fs.Args())
+	}
+	if format != "json" && format != "csv" {
+		return errors.New("inventory: format must be json or csv")
+	}
+
+	inv, err := rac.HardwareInventory(context.Background())
+	if err != nil {
+		return err
+	}
+
+	// output destination
+	var out io.Writer = os.Stdout
+	if outFilePath != "" {
+		f, err := os.Create(outFilePath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(inv)
+	} else {
+		err = writeInventoryCSV(out, inv)
+	}
+	if err != nil {
+		return err
+	}
+
+	if outFilePath != "" {
+		log.Printf("inventory: %d components written to %s", len(inv.Components()), outFilePath)
+	}
+	return nil
+}
+
+// writeInventoryCSV writes inv as csv rows of fqdd, device type, status,
+// property and value
+func writeInventoryCSV(out io.Writer, inv idrac.HardwareInventory) error {
+	w := csv.NewWriter(out)
+
+	err := w.Write([]string{"fqdd", "device_type", "status", "property", "value"})
+	if err != nil {
+		return err
+	}
+
+	for _, comp := range inv.Components() {
+		// sort properties for stable output
+		keys := make([]string, 0, len(comp.Properties))
+		for key := range comp.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			err = w.Write([]string{comp.FQDD, comp.DeviceType, comp.Status, key, comp.Properties[key]})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
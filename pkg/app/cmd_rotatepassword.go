@@ -0,0 +1,153 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gregtwallace/goracadm/pkg/idrac"
+)
+
+// userRac is the subset of idrac used to manage users
+type userRac interface {
+	FindUser(ctx context.Context, username string) (idrac.User, error)
+	ChangeUserPassword(ctx context.Context, index int, password string) error
+	SetUserPassword(ctx context.Context, index int, password string) error
+}
+
+// rotatedSecret is what is written to the secret sink
+type rotatedSecret struct {
+	Hostname  string    `json:"hostname"`
+	Username  string    `json:"username"`
+	Password  string    `json:"password"`
+	RotatedAt time.Time `json:"rotated_at"`
+}
+
+// cmdRotatePassword generates a new password for a user, changes it,
+// verifies it by logging in with a fresh client and then writes it to the
+// sink. If verification or the sink fails, the old password is restored.
+// Usage: rotatepassword -sink stdout|file:<path>|cmd:<command> [-user name] [-oldpassword pw] [-length n]
+func cmdRotatePassword(rac userRac, hostname, loginUsername, loginPassword string, args []string) error {
+	// parse command flags (options)
+	username := ""
+	oldPassword := ""
+	sink := ""
+	length := 0
+
+	fs := flag.NewFlagSet("rotatepassword", flag.ExitOnError)
+	fs.StringVar(&username, "user", "", "user to rotate (optional, default login user)")
+	fs.StringVar(&oldPassword, "oldpassword", "", "user's current password, for rollback (optional, default login password if rotating login user)")
+	fs.StringVar(&sink, "sink", "", "where to write the new secret: stdout, file:<path>, or cmd:<command> (json on stdin) (required)")
+	fs.IntVar(&length, "length", passwordDefaultLength, "password length (8 to 20)")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if len(fs.Args()) > 0 {
+		return fmt.Errorf("rotatepassword: unexpected args %v", fs.Args())
+	}
+
+	// validate
+	if username == "" {
+		username = loginUsername
+	}
+	if oldPassword == "" && username == loginUsername {
+		oldPassword = loginPassword
+	}
+	if oldPassword == "" {
+		return errors.New("rotatepassword: -oldpassword must be specified when rotating a user other than the login user (it is needed for rollback)")
+	}
+	if sink != "stdout" && !strings.HasPrefix(sink, "file:") && !strings.HasPrefix(sink, "cmd:") {
+		return errors.New("rotatepassword: sink (-sink) must be stdout, file:<path>, or cmd:<command>")
+	}
+
+	// find user and make password
+	ctx := context.Background()
+	user, err := rac.FindUser(ctx, username)
+	if err != nil {
+		return fmt.Errorf("rotatepassword: %w", err)
+	}
+
+	newPassword, err := generatePassword(length)
+	if err != nil {
+		return fmt.Errorf("rotatepassword: %w", err)
+	}
+
+	// change and verify
+	err = rac.ChangeUserPassword(ctx, user.Index, newPassword)
+	if err != nil {
+		if errors.Is(err, idrac.ErrCredentialVerification) {
+			return rollbackPassword(ctx, rac, user, oldPassword, err)
+		}
+		return fmt.Errorf("rotatepassword: %w", err)
+	}
+	log.Printf("rotatepassword: password of %s changed and verified", user.UserName)
+
+	// write secret
+	err = writeSecret(sink, rotatedSecret{
+		Hostname:  hostname,
+		Username:  user.UserName,
+		Password:  newPassword,
+		RotatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return rollbackPassword(ctx, rac, user, oldPassword, fmt.Errorf("failed to write secret to sink (%w)", err))
+	}
+	log.Printf("rotatepassword: new secret written to %s", strings.SplitN(sink, ":", 2)[0])
+
+	return nil
+}
+
+// rollbackPassword restores oldPassword after a failed rotation and returns
+// an error describing the failure and whether rollback worked
+func rollbackPassword(ctx context.Context, rac userRac, user idrac.User, oldPassword string, cause error) error {
+	log.Printf("rotatepassword: rotation failed (%s), rolling back", cause)
+
+	err := rac.SetUserPassword(ctx, user.Index, oldPassword)
+	if err != nil {
+		return fmt.Errorf("rotatepassword: rotation failed (%s) AND ROLLBACK FAILED, password of %s is unknown (%w)", cause, user.UserName, err)
+	}
+
+	return fmt.Errorf("rotatepassword: rotation failed, old password of %s restored (%w)", user.UserName, cause)
+}
+
+// writeSecret writes secret as json to the sink
+func writeSecret(sink string, secret rotatedSecret) error {
+	data, err := json.Marshal(secret)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	sinkType, target, _ := strings.Cut(sink, ":")
+	switch sinkType {
+	case "stdout":
+		_, err = os.Stdout.Write(data)
+		return err
+
+	case "file":
+		return os.WriteFile(target, data, 0600)
+
+	case "cmd":
+		fields := strings.Fields(target)
+		if len(fields) == 0 {
+			return errors.New("sink command is empty")
+		}
+		cmd := exec.Command(fields[0], fields[1:]...)
+		cmd.Stdin = bytes.NewReader(data)
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	return fmt.Errorf("unknown sink (%s)", sinkType)
+}
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/gregtwallace/goracadm/pkg/idrac"
+)
+
+// safeSetNICPollInterval is how often the new address is polled
+const safeSetNICPollInterval = 5 * time.Second
+
+// nicRac is the subset of idrac used to change network settings
+type nicRac interface {
+	GetNICConfig(ctx context.Context) (idrac.NICConfig, error)
+	SetNICCfg(ctx context.Context, flags []string) error
+}
+
+// cmdSafeSetNICCfg runs setniccfg and then polls the idrac (at its new address
+// if -s changed it), logging in and reading getniccfg until it reports the
+// requested settings or the timeout passes. If it doesn't, exact recovery
+// steps are printed.
+// Usage: safesetniccfg [-timeout 3m] <setniccfg options>
+func cmdSafeSetNICCfg(rac nicRac, hostname, username, password string, args []string) error {
+	// parse command flags (options); the rest are setniccfg's
+	timeout := time.Duration(0)
+
+	fs := flag.NewFlagSet("safesetniccfg", flag.ExitOnError)
+	fs.DurationVar(&timeout, "timeout", 3*time.Minute, "how long to wait for the idrac to answer after the change")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	setArgs := fs.Args()
+	if len(setArgs) == 0 {
+		return errors.New("safesetniccfg: setniccfg options (-d, -s, -o, or -v) must be specified")
+	}
+
+	// save current settings for recovery
+	ctx := context.Background()
+	oldCfg, err := rac.GetNICConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("safesetniccfg: failed to read current settings, not changing anything (%w)", err)
+	}
+
+	// work out where the idrac should answer afterwards
+	newHostname := hostname
+	switch setArgs[0] {
+	case "-s":
+		if len(setArgs) > 1 {
+			newHostname = setArgs[1]
+			if _, port, err := net.SplitHostPort(hostname); err == nil {
+				newHostname = net.JoinHostPort(setArgs[1], port)
+			}
+		}
+	case "-d":
+		log.Printf("safesetniccfg: WARNING: the dhcp assigned address isn't known, polling %s (this will only succeed if dhcp assigns the same address)", hostname)
+	case "-o":
+		log.Printf("safesetniccfg: WARNING: -o changes whether the nic is enabled, the idrac may not answer")
+	}
+
+	// change settings
+	err = rac.SetNICCfg(ctx, setArgs)
+	if err != nil {
+		return fmt.Errorf("safesetniccfg: setniccfg failed (%w)", err)
+	}
+	log.Printf("safesetniccfg: settings changed, waiting up to %s for idrac to answer at %s", timeout, newHostname)
+
+	// poll new address; certificate won't match a new ip, so don't be strict
+	// about it
+	newRac, err := idrac.NewIdrac(newHostname, username, password, false)
+	if err != nil {
+		return err
+	}
+
+	pollCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// give the idrac a moment to apply the change before first poll; the old
+	// settings can still be answering at first, so success is only when
+	// getniccfg reports the requested settings
+	ticker := time.NewTicker(safeSetNICPollInterval)
+	defer ticker.Stop()
+	loggedIn := false
+	var lastErr error
+	for {
+		select {
+		case <-pollCtx.Done():
+			printNICRecovery(hostname, newHostname, oldCfg)
+			return fmt.Errorf("safesetniccfg: idrac did not report the new settings at %s within %s (last: %s)", newHostname, timeout, lastErr)
+		case <-ticker.C:
+		}
+
+		if !loggedIn {
+			// discover first: it answers without credentials, so this
+			// confirms an idrac is at the new address before logging in
+			_, lastErr = newRac.Discover()
+			if lastErr != nil {
+				continue
+			}
+			_, lastErr = newRac.Login()
+			if lastErr != nil {
+				continue
+			}
+			loggedIn = true
+		}
+
+		cfg, err := newRac.GetNICConfig(pollCtx)
+		if err != nil {
+			// the session may not survive the change, log in again
+			lastErr = err
+			loggedIn = false
+			continue
+		}
+
+		lastErr = nicSettingsPending(setArgs, cfg)
+		if lastErr == nil {
+			log.Printf("safesetniccfg: success, idrac is answering at %s with the new settings", newHostname)
+			_, _ = newRac.Logout()
+			return nil
+		}
+	}
+}
+
+// nicSettingsPending returns an error describing how cfg differs from the
+// settings setArgs (setniccfg options) requested, or nil if they're applied
+func nicSettingsPending(setArgs []string, cfg idrac.NICConfig) error {
+	switch {
+	case setArgs[0] == "-d" && !cfg.DHCPEnabled:
+		return errors.New("dhcp is not yet enabled")
+
+	case setArgs[0] == "-s" && len(setArgs) == 4:
+		if cfg.DHCPEnabled || cfg.IPAddress != setArgs[1] || cfg.SubnetMask != setArgs[2] || cfg.Gateway != setArgs[3] {
+			return fmt.Errorf("static settings not yet applied (dhcp=%t ip=%s netmask=%s gateway=%s)", cfg.DHCPEnabled, cfg.IPAddress, cfg.SubnetMask, cfg.Gateway)
+		}
+
+	case setArgs[0] == "-o" && !cfg.NICEnabled:
+		return errors.New("nic is not yet enabled")
+
+	case setArgs[0] == "-v" && len(setArgs) == 1 && cfg.VLANEnabled:
+		return errors.New("vlan is not yet disabled")
+
+	case setArgs[0] == "-v" && len(setArgs) == 3:
+		if !cfg.VLANEnabled || fmt.Sprint(cfg.VLANID) != setArgs[1] || fmt.Sprint(cfg.VLANPriority) != setArgs[2] {
+			return fmt.Errorf("vlan settings not yet applied (vlan=%t id=%d priority=%d)", cfg.VLANEnabled, cfg.VLANID, cfg.VLANPriority)
+		}
+	}
+
+	return nil
+}
+
+// printNICRecovery prints how to restore the network settings that were in
+// place before the change
+func printNICRecovery(oldHostname, newHostname string, oldCfg idrac.NICConfig) {
+	restore := "racadm setniccfg -d"
+	if !oldCfg.DHCPEnabled {
+		restore = fmt.Sprintf("racadm setniccfg -s %s %s %s", oldCfg.IPAddress, oldCfg.SubnetMask, oldCfg.Gateway)
+	}
+	restoreVLAN := "racadm setniccfg -v"
+	if oldCfg.VLANEnabled {
+		restoreVLAN = fmt.Sprintf("racadm setniccfg -v %d %d", oldCfg.VLANID, oldCfg.VLANPriority)
+	}
+
+	fmt.Printf(`
+RECOVERY: the idrac did not report the new settings at %s after the change.
+Previous settings: dhcp=%t ip=%s netmask=%s gateway=%s vlan=%t (id %d, priority %d)
+
+To restore them, use one of the following:
+ 1. From the server's operating system (local racadm, no network needed):
+      %s
+      %s
+ 2. From the server's console: reboot, press F2 (System Setup) and open
+    iDRAC Settings > Network, then re-enter the settings above.
+ 3. From the front panel LCD (if fitted): View/Setup > iDRAC > IP settings.
+ 4. If the new settings are correct but unreachable from here (e.g. a
+    different subnet or vlan), try from a host on that network:
+      goracadm -r %s -u <user> -p <password> getniccfg
+Previous address was %s.
+`, newHostname, oldCfg.DHCPEnabled, oldCfg.IPAddress, oldCfg.SubnetMask, oldCfg.Gateway,
+		oldCfg.VLANEnabled, oldCfg.VLANID, oldCfg.VLANPriority,
+		restore, restoreVLAN, newHostname, oldHostname)
+}
@@ -0,0 +1,221 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gregtwallace/goracadm/pkg/idrac"
+)
+
+// scpRac is the subset of idrac used to export and import server
+// configuration profiles
+type scpRac interface {
+	ExportSCP(ctx context.Context, format idrac.SCPFormat, filters []string) (idrac.SystemConfiguration, error)
+	ImportSCP(ctx context.Context, sc idrac.SystemConfiguration, format idrac.SCPFormat, filters []string, opts idrac.SCPImportOptions) (idrac.Job, error)
+	ImportSCPData(ctx context.Context, content []byte, opts idrac.SCPImportOptions) (idrac.Job, error)
+}
+
+// splitComponents splits a comma separated -c value
+func splitComponents(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// readSCP reads and parses a profile file
+func readSCP(path string) (idrac.SystemConfiguration, idrac.SCPFormat, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return idrac.SystemConfiguration{}, "", err
+	}
+
+	sc, format, err := idrac.ParseSCP(data)
+	if err != nil {
+		return idrac.SystemConfiguration{}, "", fmt.Errorf("%s: %w", path, err)
+	}
+
+	return sc, format, nil
+}
+
+// isLocalSCPCmd returns true for the scp operations that only work on local
+// files (so don't need an idrac)
+func isLocalSCPCmd(args []string) bool {
+	return len(args) > 1 && args[0] == "scp" && (args[1] == "diff" || args[1] == "filter")
+}
+
+// cmdSCP exports or imports a server configuration profile, or diffs or
+// filters local profile files.
+// Usage: scp export -f <file> [-t xml|json] [-c components]
+// scp import -f <file> [-c components] [-b Graceful|Forced|NoReboot] [-w minutes] [-off] [-timeout 1h]
+// scp diff [-c components] [-json] <file> <file>
+// scp filter -c <components> -f <file> -o <file>
+func cmdSCP(rac scpRac, args []string) error {
+	if len(args) == 0 {
+		return errors.New("scp: operation (export, import, diff, or filter) must be specified")
+	}
+	operation := args[0]
+	args = args[1:]
+
+	// parse command flags (options)
+	file := ""
+	outFile := ""
+	format := ""
+	components := ""
+	shutdownType := ""
+	waitMinutes := 0
+	endPowerOff := false
+	jsonOutput := false
+	timeout := time.Duration(0)
+
+	fs := flag.NewFlagSet("scp "+operation, flag.ExitOnError)
+	fs.StringVar(&components, "c", "", "comma separated components to keep, by FQDD or type (e.g. BIOS,iDRAC,RAID.Integrated.1-1)")
+	switch operation {
+	case "export":
+		fs.StringVar(&file, "f", "", "file to write the profile to (required)")
+		fs.StringVar(&format, "t", "xml", "profile format, xml or json")
+	case "import":
+		fs.StringVar(&file, "f", "", "profile file to import (required)")
+		fs.StringVar(&shutdownType, "b", "", "shutdown type: Graceful, Forced, or NoReboot (default Graceful)")
+		fs.IntVar(&waitMinutes, "w", 0, "minutes to wait for a graceful shutdown")
+		fs.BoolVar(&endPowerOff, "off", false, "leave the server off after the import")
+		fs.DurationVar(&timeout, "timeout", time.Hour, "how long to wait for the import job")
+	case "diff":
+		fs.BoolVar(&jsonOutput, "json", false, "print the differences as json")
+	case "filter":
+		fs.StringVar(&file, "f", "", "profile file to filter (required)")
+		fs.StringVar(&outFile, "o", "", "file to write the filtered profile to (required)")
+	default:
+		return fmt.Errorf("scp: invalid operation %s (must be export, import, diff, or filter)", operation)
+	}
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if operation != "diff" && len(fs.Args()) > 0 {
+		return fmt.Errorf("scp: unexpected args %v", fs.Args())
+	}
+	if operation != "diff" && file == "" {
+		return errors.New("scp: file (-f) must be specified")
+	}
+	filters := splitComponents(components)
+
+	switch operation {
+	case "export":
+		sc, err := rac.ExportSCP(context.Background(), idrac.SCPFormat(format), filters)
+		if err != nil {
+			return fmt.Errorf("scp: export failed (%w)", err)
+		}
+		return writeSCP(sc, idrac.SCPFormat(format), file)
+
+	case "import":
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		opts := idrac.SCPImportOptions{
+			ShutdownType: idrac.SCPShutdownType(shutdownType),
+			WaitMinutes:  waitMinutes,
+			EndPowerOff:  endPowerOff,
+		}
+
+		// without filters, the file is imported unchanged
+		var job idrac.Job
+		if len(filters) == 0 {
+			content, readErr := os.ReadFile(file)
+			if readErr != nil {
+				return fmt.Errorf("scp: %w", readErr)
+			}
+			job, err = rac.ImportSCPData(ctx, content, opts)
+		} else {
+			sc, scFormat, readErr := readSCP(file)
+			if readErr != nil {
+				return fmt.Errorf("scp: %w", readErr)
+			}
+			job, err = rac.ImportSCP(ctx, sc, scFormat, filters, opts)
+		}
+		if err != nil {
+			return fmt.Errorf("scp: import failed (%w)", err)
+		}
+		fmt.Printf("import job %s: %s\n", job.ID, job.Message)
+		return nil
+
+	case "diff":
+		if len(fs.Args()) != 2 {
+			return errors.New("scp: diff needs exactly two profile files")
+		}
+		from, _, err := readSCP(fs.Arg(0))
+		if err != nil {
+			return fmt.Errorf("scp: %w", err)
+		}
+		to, _, err := readSCP(fs.Arg(1))
+		if err != nil {
+			return fmt.Errorf("scp: %w", err)
+		}
+
+		diffs := idrac.DiffSCP(from.FilterComponents(filters), to.FilterComponents(filters))
+		if jsonOutput {
+			return writeJSON(diffs)
+		}
+		printSCPDiff(diffs)
+		return nil
+
+	case "filter":
+		if outFile == "" {
+			return errors.New("scp: output file (-o) must be specified")
+		}
+		sc, scFormat, err := readSCP(file)
+		if err != nil {
+			return fmt.Errorf("scp: %w", err)
+		}
+		return writeSCP(sc.FilterComponents(filters), scFormat, outFile)
+	}
+
+	return nil
+}
+
+// writeSCP writes the profile to path in format
+func writeSCP(sc idrac.SystemConfiguration, format idrac.SCPFormat, path string) error {
+	data, err := sc.Marshal(format)
+	if err != nil {
+		return fmt.Errorf("scp: %w", err)
+	}
+
+	err = os.WriteFile(path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("scp: %w", err)
+	}
+
+	return nil
+}
+
+// printSCPDiff prints the differences in a diff like format
+func printSCPDiff(diffs []idrac.SCPDifference) {
+	if len(diffs) == 0 {
+		fmt.Println("profiles are identical")
+		return
+	}
+
+	component := ""
+	for _, diff := range diffs {
+		if diff.Component != component {
+			component = diff.Component
+			fmt.Printf("[%s]\n", component)
+		}
+
+		switch diff.Change {
+		case idrac.SCPChangeAdded:
+			fmt.Printf("  + %s = %s\n", diff.Attribute, diff.New)
+		case idrac.SCPChangeRemoved:
+			fmt.Printf("  - %s = %s\n", diff.Attribute, diff.Old)
+		case idrac.SCPChangeChanged:
+			fmt.Printf("  ~ %s: %s -> %s\n", diff.Attribute, diff.Old, diff.New)
+		}
+	}
+	fmt.Printf("%d difference(s)\n", len(diffs))
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gregtwallace/goracadm/pkg/idrac"
+)
+
+// tsrRac is the subset of idrac used to collect technical support reports
+type tsrRac interface {
+	CollectTSR(ctx context.Context, selectors []idrac.TSRSelector) (idrac.Job, error)
+	ExportTSRToShare(ctx context.Context, file string, share idrac.NetworkShare) error
+}
+
+// cmdTSR collects a technical support report, waits for the collection and
+// then exports it to a network share (e.g. to attach to a support ticket).
+// The idrac can't return the report itself, so only share export is
+// supported.
+// Usage: tsr -l share [-u user] [-p password] [-t SysInfo,TTYLog,...] [-f file] [-timeout 30m]
+func cmdTSR(rac tsrRac, hostname string, args []string) error {
+	// parse command flags (options)
+	selectors := ""
+	file := ""
+	share := idrac.NetworkShare{}
+	timeout := time.Duration(0)
+
+	fs := flag.NewFlagSet("tsr", flag.ExitOnError)
+	fs.StringVar(&selectors, "t", "", "comma separated data selectors: SysInfo, TTYLog, OSAppAll, OSAppNoPII, Debug (default: the idrac's)")
+	fs.StringVar(&share.Path, "l", "", "network share to export to, e.g. //server/share or server:/path (required)")
+	fs.StringVar(&share.Username, "u", "", "network share username (optional)")
+	fs.StringVar(&share.Password, "p", "", "network share password (optional)")
+	fs.StringVar(&file, "f", "", "report file name on the share (default <hostname>-<time>.tsr.zip)")
+	fs.DurationVar(&timeout, "timeout", 30*time.Minute, "how long to wait for the collection and export")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if len(fs.Args()) > 0 {
+		return fmt.Errorf("tsr: unexpected args %v", fs.Args())
+	}
+	if share.Path == "" {
+		return errors.New("tsr: network share (-l) must be specified, the report can only be exported to a share")
+	}
+	if file == "" {
+		file = fmt.Sprintf("%s-%s.tsr.zip", hostFileName(hostname), time.Now().Format("20060102-150405"))
+	}
+
+	tsrSelectors := []idrac.TSRSelector{}
+	if selectors != "" {
+		for _, selector := range strings.Split(selectors, ",") {
+			tsrSelectors = append(tsrSelectors, idrac.TSRSelector(strings.TrimSpace(selector)))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	job, err := rac.CollectTSR(ctx, tsrSelectors)
+	if err == nil {
+		log.Printf("tsr: collection job %s complete", job.ID)
+		err = rac.ExportTSRToShare(ctx, file, share)
+	}
+	if err != nil {
+		return fmt.Errorf("tsr: %w", err)
+	}
+
+	log.Printf("tsr: report exported to %s on %s", file, share.Path)
+	return nil
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gregtwallace/goracadm/pkg/idrac"
+)
+
+// firmwareRac is the subset of idrac used to update firmware
+type firmwareRac interface {
+	UpdateFirmware(ctx context.Context, imagePath string) (idrac.FirmwareVersion, error)
+}
+
+// cmdUpdateFirmware updates the idrac firmware from a local image and waits
+// for the update, the idrac's reset, and its return.
+// Usage: updatefirmware [-timeout 45m] -f <image file>
+func cmdUpdateFirmware(rac firmwareRac, args []string) error {
+	// parse command flags (options)
+	imagePath := ""
+	timeout := time.Duration(0)
+
+	fs := flag.NewFlagSet("updatefirmware", flag.ExitOnError)
+	fs.StringVar(&imagePath, "f", "", "local firmware image file (.d7, .d9, or Dell Update Package .exe)")
+	fs.DurationVar(&timeout, "timeout", 45*time.Minute, "how long to wait for the update and the idrac's return")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if len(fs.Args()) > 0 {
+		return fmt.Errorf("updatefirmware: unexpected args %v", fs.Args())
+	}
+	if imagePath == "" {
+		return errors.New("updatefirmware: image file (-f) must be specified")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	version, err := rac.UpdateFirmware(ctx, imagePath)
+	if err != nil {
+		return fmt.Errorf("updatefirmware: %w", err)
+	}
+
+	log.Printf("updatefirmware: idrac firmware version is now %s", version)
+	return nil
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/gregtwallace/goracadm/pkg/idrac"
+)
+
+// jsonParsers are the racadm subcommands that support -json output, each
+// with the function to parse the subcommand's output
+var jsonParsers = map[string]func(output string) any{
+	"fru":           func(output string) any { return idrac.ParseFRUs(output) },
+	"getmacaddress": func(output string) any { return idrac.ParseModuleMACAddresses(output) },
+	"getniccfg":     func(output string) any { return idrac.ParseNICConfig(output) },
+	"getraclog":     func(output string) any { return idrac.ParseLogRecords(output) },
+	"getsel":        func(output string) any { return idrac.ParseSELRecords(output) },
+	"getssninfo":    func(output string) any { return idrac.ParseSessions(output) },
+	"getsysinfo":    func(output string) any { return idrac.ParseSystemInfo(output) },
+	"getversion":    func(output string) any { return idrac.ParseFirmwareInventory(output) },
+	"hwinventory":   func(output string) any { return idrac.ParseHardwareInventory(output) },
+	"jobqueue":      func(output string) any { return idrac.ParseJobs(output) },
+	"lclog":         func(output string) any { return idrac.ParseLogRecords(output) },
+	"license":       func(output string) any { return idrac.ParseLicenses(output) },
+	"raid":          func(output string) any { return idrac.ParseStorage(output) },
+	"storage":       func(output string) any { return idrac.ParseStorage(output) },
+}
+
+// printJSON parses the output of cmd and writes it to stdout as json
+func printJSON(cmd string, output string) error {
+	parse, ok := jsonParsers[cmd]
+	if !ok {
+		return fmt.Errorf("json output is not supported for %s", cmd)
+	}
+
+	return writeJSON(parse(output))
+}
+
+// writeJSON writes v to stdout as indented json
+func writeJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -1,7 +1,10 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 
@@ -19,15 +22,34 @@ func Start() {
 	username := ""
 	password := ""
 	strictCerts := false
+	jsonOutput := false
+	changeDefaultPassword := ""
+	confirmToken := ""
+	confirmHostname := ""
+	snapshotPath := ""
 
 	// parse command line
 	flag.StringVar(&hostname, "r", "", "idrac hostname or ip address (and port)")
 	flag.StringVar(&username, "u", "", "idrac username")
 	flag.StringVar(&password, "p", "", "idrac password")
 	flag.BoolVar(&strictCerts, "S", false, "strictly require validated certs")
+	flag.StringVar(&changeDefaultPassword, "changedefaultpw", "", "if the idrac reports default credentials are in use, change the login user's password to this right after login")
+	flag.BoolVar(&jsonOutput, "json", false, "print the subcommand's parsed output as json (if supported)")
+	flag.StringVar(&confirmToken, "confirm", "", "confirmation token for a destructive subcommand (racresetcfg, sslresetcfg, clrsel)")
+	flag.StringVar(&confirmHostname, "confirmhost", "", "the idrac's DNS RAC name (see getsysinfo), echoed back to confirm a destructive subcommand")
+	flag.StringVar(&snapshotPath, "snapshot", "", "file for the backup taken before a destructive subcommand (default <hostname>-<time>.pre-<subcommand>.tar.gz)")
 
 	flag.Parse()
 
+	// local only subcommands (no idrac needed)
+	if isLocalSCPCmd(flag.Args()) {
+		err := cmdSCP(nil, flag.Args()[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// make idrac
 	rac, err := idrac.NewIdrac(hostname, username, password, strictCerts)
 	if err != nil {
@@ -41,21 +63,102 @@ func Start() {
 	}
 
 	// login to idrac and save the sid cookie
-	_, err = rac.Login()
+	loginResp, err := rac.Login()
 	if err != nil {
 		log.Fatalf("login error: %s", err)
 	}
+	if version, err := rac.FirmwareVersion(context.Background()); err == nil {
+		log.Printf("idrac firmware version: %s", version.Raw)
+	} else {
+		log.Printf("unable to read idrac firmware version (%s)", err)
+	}
+	if loginResp.State != idrac.LoginStateOK && loginResp.State != idrac.LoginStateUnknown {
+		log.Printf("WARNING: idrac reports login state %s", loginResp.State)
+	}
+
+	// default credentials in use?
+	if loginResp.DefaultCredentialsInUse {
+		log.Println("WARNING: ********************************************************")
+		log.Println("WARNING: the idrac reports DEFAULT CREDENTIALS (e.g. root/calvin) are in use")
+		log.Println("WARNING: change the password (see -changedefaultpw)")
+		log.Println("WARNING: ********************************************************")
+
+		if changeDefaultPassword != "" {
+			err = rac.ChangeOwnPassword(context.Background(), changeDefaultPassword)
+			if err != nil {
+				log.Printf("failed to change default password: %s", err)
+				_, _ = rac.Logout()
+				os.Exit(1)
+			}
+			log.Println("default password changed and verified")
+		}
+	}
 
 	// get subcommand and flags
 	cmd := flag.Args()[0]
 	flags := flag.Args()[1:]
 
-	// execute the subcommand
-	_, err = rac.Exec(cmd, flags)
+	// execute the subcommand (goracadm specific subcommands, else racadm
+	// subcommand)
+	switch cmd {
+	case "apply":
+		err = cmdApply(rac, hostname, flags)
+	case "backup":
+		err = cmdBackup(rac, hostname, flags)
+	case "inventory":
+		err = cmdInventory(rac, flags)
+	case "restore":
+		err = cmdRestore(rac, hostname, flags)
+	case "rotatepassword":
+		err = cmdRotatePassword(rac, hostname, username, password, flags)
+	case "safesetniccfg":
+		err = cmdSafeSetNICCfg(rac, hostname, username, password, flags)
+	case "scp":
+		err = cmdSCP(rac, flags)
+	case "tsr":
+		err = cmdTSR(rac, hostname, flags)
+	case "updatefirmware":
+		err = cmdUpdateFirmware(rac, flags)
+	default:
+		if jsonOutput && jsonParsers[cmd] == nil {
+			err = fmt.Errorf("json output is not supported for %s", cmd)
+			break
+		}
+
+		// destructive subcommands are guarded: confirm, snapshot, then run
+		if idrac.IsDestructive(cmd) {
+			snapshot, snapshotErr := createSnapshotFile(hostname, cmd, snapshotPath)
+			if snapshotErr != nil {
+				err = snapshotErr
+				break
+			}
+			_, err = rac.ExecDestructive(context.Background(), cmd, flags, idrac.DestructiveConfirmation{
+				Token:    confirmToken,
+				Hostname: confirmHostname,
+				Snapshot: snapshot,
+			})
+			closeSnapshotFile(snapshot, err)
+			if errors.Is(err, idrac.ErrConfirmationRequired) {
+				log.Printf("%s is destructive: rerun with -confirm <token> -confirmhost <idrac dns rac name>", cmd)
+			}
+			break
+		}
+
+		execResp, execErr := rac.Exec(cmd, flags)
+		err = execErr
+		if err == nil && jsonOutput {
+			err = printJSON(cmd, execResp.Response.CommandOutput)
+		}
+	}
 	if err != nil {
 		// not fatal, continue to logout and change exit code to error
 		log.Printf("exec error: %s", err)
 		exitCode = 1
+
+		// drift gets its own exit code so ci can tell it from failure
+		if errors.Is(err, errDrift) {
+			exitCode = 2
+		}
 	}
 
 	// logout of the idrac
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+// password character classes. The special characters are limited to ones
+// the idrac accepts and that don't need quoting in racadm or a shell,
+// anywhere in the password. Left out are - (a leading - is read as an
+// option by racadm), ! (history expansion), # and ~ (special when leading)
+// and ^ (special in some shells).
+const (
+	passwordLower   = "abcdefghijkmnopqrstuvwxyz"
+	passwordUpper   = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	passwordDigits  = "23456789"
+	passwordSpecial = "%+.@_"
+
+	// idrac7/8 passwords are limited to 20 characters
+	passwordMaxLength     = 20
+	passwordDefaultLength = 20
+	passwordMinLength     = 8
+)
+
+// generatePassword returns a random password of length that contains at
+// least one lower case, upper case, digit and special character (which
+// satisfies the idrac's strongest password policy)
+func generatePassword(length int) (string, error) {
+	if length < passwordMinLength || length > passwordMaxLength {
+		return "", errors.New("password length must be between 8 and 20")
+	}
+
+	classes := []string{passwordLower, passwordUpper, passwordDigits, passwordSpecial}
+	all := passwordLower + passwordUpper + passwordDigits + passwordSpecial
+
+	// one of each class, then fill from all
+	password := make([]byte, 0, length)
+	for _, class := range classes {
+		c, err := randomChar(class)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+	for len(password) < length {
+		c, err := randomChar(all)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	// shuffle so the class characters aren't always first
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
+
+// randomChar returns a random character from chars
+func randomChar(chars string) (byte, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, err
+	}
+
+	return chars[i.Int64()], nil
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gregtwallace/goracadm/pkg/idrac"
+)
+
+// createSnapshotFile creates the file for the snapshot taken before the
+// destructive subcommand cmd (path, or a default name if empty). It is never
+// overwritten if it already exists.
+func createSnapshotFile(hostname, cmd, path string) (*os.File, error) {
+	if path == "" {
+		path = fmt.Sprintf("%s-%s.pre-%s.tar.gz", hostFileName(hostname), time.Now().Format("20060102-150405"), cmd)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot file (%w)", err)
+	}
+
+	return f, nil
+}
+
+// closeSnapshotFile closes the snapshot file and removes it if nothing was
+// written to it (the command wasn't confirmed) or the snapshot failed (a
+// partly written snapshot is corrupt)
+func closeSnapshotFile(f *os.File, execErr error) {
+	info, statErr := f.Stat()
+	_ = f.Close()
+
+	if (statErr == nil && info.Size() == 0) || errors.Is(execErr, idrac.ErrSnapshotFailed) {
+		_ = os.Remove(f.Name())
+		return
+	}
+
+	if execErr != nil {
+		log.Printf("pre-reset snapshot written to %s (the subcommand failed)", f.Name())
+		return
+	}
+	log.Printf("pre-reset snapshot written to %s (restore it with: restore -f %s)", f.Name(), f.Name())
+}
@@ -0,0 +1,313 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gregtwallace/goracadm/pkg/idrac"
+	"gopkg.in/yaml.v3"
+)
+
+// stateRac is the subset of idrac used to read and write settings
+type stateRac interface {
+	Get(ctx context.Context, key string) (idrac.AttributeTree, error)
+	Set(ctx context.Context, key, value string) (idrac.SetResult, error)
+	GetConfig(ctx context.Context, group string, index int) (idrac.AttributeTree, error)
+	Config(ctx context.Context, group, object string, index int, value string) error
+}
+
+// stateSettings are the desired values for a host or group. Attributes are
+// dotted get/set keys (iDRAC8/9) and objects are legacy getconfig/config
+// keys in the form cfgGroup.cfgObject or cfgGroup.index.cfgObject (iDRAC6/7).
+type stateSettings struct {
+	Attributes map[string]string `yaml:"attributes" json:"attributes"`
+	Objects    map[string]string `yaml:"objects" json:"objects"`
+}
+
+// stateHost is a host's desired settings, applied on top of its groups
+type stateHost struct {
+	Groups        []string `yaml:"groups" json:"groups"`
+	stateSettings `yaml:",inline"`
+}
+
+// stateFile is the desired state file (yaml or json)
+type stateFile struct {
+	Defaults stateSettings            `yaml:"defaults" json:"defaults"`
+	Groups   map[string]stateSettings `yaml:"groups" json:"groups"`
+	Hosts    map[string]stateHost     `yaml:"hosts" json:"hosts"`
+}
+
+// loadStateFile reads the desired state file at path. JSON is a subset of
+// YAML, so both are parsed by the yaml decoder.
+func loadStateFile(path string) (*stateFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &stateFile{}
+	err = yaml.Unmarshal(data, state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse state file (%w)", err)
+	}
+
+	return state, nil
+}
+
+// settingsFor merges defaults, the host's groups (in order) and the host's
+// own settings into the desired settings for hostname
+func (state *stateFile) settingsFor(hostname string) (stateSettings, error) {
+	merged := stateSettings{
+		Attributes: make(map[string]string),
+		Objects:    make(map[string]string),
+	}
+	merge := func(s stateSettings) {
+		for k, v := range s.Attributes {
+			merged.Attributes[k] = v
+		}
+		for k, v := range s.Objects {
+			merged.Objects[k] = v
+		}
+	}
+
+	merge(state.Defaults)
+
+	host, ok := state.Hosts[hostname]
+	if !ok && len(state.Hosts) > 0 {
+		return stateSettings{}, fmt.Errorf("host %s not found in state file", hostname)
+	}
+
+	for _, groupName := range host.Groups {
+		group, ok := state.Groups[groupName]
+		if !ok {
+			return stateSettings{}, fmt.Errorf("group %s (host %s) not found in state file", groupName, hostname)
+		}
+		merge(group)
+	}
+	merge(host.stateSettings)
+
+	return merged, nil
+}
+
+// planAction is what applying a plan will do for one setting
+type planAction string
+
+const (
+	planUnchanged planAction = "unchanged"
+	planChange    planAction = "change"
+	planWriteOnly planAction = "write-only" // value can't be read, always written
+	planError     planAction = "error"
+)
+
+// planItem is the plan for a single attribute or object
+type planItem struct {
+	Key     string     `json:"key"`
+	Legacy  bool       `json:"legacy,omitempty"`
+	Current string     `json:"current"`
+	Desired string     `json:"desired"`
+	Action  planAction `json:"action"`
+	Err     string     `json:"error,omitempty"`
+}
+
+// plan is the list of planned changes, sorted by key
+type plan []planItem
+
+// makePlan compares desired settings to the live values on rac
+func makePlan(ctx context.Context, rac stateRac, desired stateSettings) plan {
+	p := plan{}
+
+	// attributes (get/set)
+	for key, value := range desired.Attributes {
+		item := planItem{Key: key, Desired: value}
+
+		tree, err := rac.Get(ctx, key)
+		if err != nil {
+			item.Action = planError
+			item.Err = err.Error()
+			p = append(p, item)
+			continue
+		}
+
+		p = append(p, item.compare(tree.Attribute(key)))
+	}
+
+	// legacy objects (getconfig/config), read once per group
+	groupTrees := make(map[string]idrac.AttributeTree)
+	groupErrs := make(map[string]error)
+	for key, value := range desired.Objects {
+		item := planItem{Key: key, Legacy: true, Desired: value}
+
+		group, index, object, err := splitObjectKey(key)
+		if err != nil {
+			item.Action = planError
+			item.Err = err.Error()
+			p = append(p, item)
+			continue
+		}
+
+		groupKey := fmt.Sprintf("%s.%d", group, index)
+		tree, ok := groupTrees[groupKey]
+		if !ok && groupErrs[groupKey] == nil {
+			tree, err = rac.GetConfig(ctx, group, index)
+			groupTrees[groupKey] = tree
+			groupErrs[groupKey] = err
+		}
+		if groupErrs[groupKey] != nil {
+			item.Action = planError
+			item.Err = groupErrs[groupKey].Error()
+			p = append(p, item)
+			continue
+		}
+
+		p = append(p, item.compare(tree.Attribute(object)))
+	}
+
+	sort.Slice(p, func(i, j int) bool {
+		if p[i].Legacy != p[j].Legacy {
+			return !p[i].Legacy
+		}
+		return p[i].Key < p[j].Key
+	})
+
+	return p
+}
+
+// compare sets the current value and action of item based on the live attr
+func (item planItem) compare(attr idrac.Attribute, found bool) planItem {
+	switch {
+	case !found:
+		item.Action = planError
+		item.Err = "not found on idrac"
+	case attr.ReadOnly:
+		item.Current = attr.Value
+		item.Action = planError
+		item.Err = "read only"
+	case attr.WriteOnly:
+		item.Action = planWriteOnly
+	default:
+		item.Current = attr.Value
+		// a pending value is what the setting will become, so diff against it
+		if attr.Pending {
+			item.Current = attr.PendingValue
+		}
+		item.Action = planUnchanged
+		if item.Current != item.Desired {
+			item.Action = planChange
+		}
+	}
+
+	return item
+}
+
+// splitObjectKey splits a legacy object key (cfgGroup.cfgObject or
+// cfgGroup.index.cfgObject) into its parts
+func splitObjectKey(key string) (group string, index int, object string, err error) {
+	parts := strings.Split(key, ".")
+	switch len(parts) {
+	case 2:
+		return parts[0], 0, parts[1], nil
+	case 3:
+		index, err = strconv.Atoi(parts[1])
+		if err == nil && index > 0 {
+			return parts[0], index, parts[2], nil
+		}
+	}
+
+	return "", 0, "", errors.New("invalid object key (must be group.object or group.index.object)")
+}
+
+// secretKeyWords mark a setting key whose value is a secret
+var secretKeyWords = []string{"password", "passphrase", "secret"}
+
+// isSecretKey returns true if the setting key holds a secret
+func isSecretKey(key string) bool {
+	lowerKey := strings.ToLower(key)
+	for _, word := range secretKeyWords {
+		if strings.Contains(lowerKey, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// displayValue returns value as it may be printed or logged: quoted, or
+// hidden if the setting is a secret or write-only
+func (p plan) displayValue(key, value string) string {
+	if isSecretKey(key) {
+		return "(hidden)"
+	}
+	for _, item := range p {
+		if item.Key == key && item.Action == planWriteOnly {
+			return "(hidden)"
+		}
+	}
+
+	return strconv.Quote(value)
+}
+
+// counts returns the number of items to change, write-only items (which
+// are always written but aren't drift) and items in error
+func (p plan) counts() (changes, writeOnly, errs int) {
+	for _, item := range p {
+		switch item.Action {
+		case planChange:
+			changes++
+		case planWriteOnly:
+			writeOnly++
+		case planError:
+			errs++
+		}
+	}
+
+	return changes, writeOnly, errs
+}
+
+// print writes the plan in human readable form
+func (p plan) print(hostname string) {
+	fmt.Printf("plan for %s:\n", hostname)
+	for _, item := range p {
+		switch item.Action {
+		case planChange:
+			fmt.Printf("  ~ %s: %s => %s\n", item.Key, p.displayValue(item.Key, item.Current), p.displayValue(item.Key, item.Desired))
+		case planWriteOnly:
+			fmt.Printf("  + %s: (write-only) => set\n", item.Key)
+		case planError:
+			fmt.Printf("  ! %s: %s\n", item.Key, item.Err)
+		}
+	}
+
+	changes, writeOnly, errs := p.counts()
+	fmt.Printf("%d to change, %d write-only, %d unchanged, %d errors\n", changes, writeOnly, len(p)-changes-writeOnly-errs, errs)
+}
+
+// apply writes every item in the plan that isn't unchanged
+func (p plan) apply(ctx context.Context, rac stateRac) (results []idrac.SetResult, err error) {
+	for _, item := range p {
+		if item.Action != planChange && item.Action != planWriteOnly {
+			continue
+		}
+
+		if !item.Legacy {
+			result, err := rac.Set(ctx, item.Key, item.Desired)
+			if err != nil {
+				return results, fmt.Errorf("failed to set %s (%w)", item.Key, err)
+			}
+			results = append(results, result)
+			continue
+		}
+
+		group, index, object, _ := splitObjectKey(item.Key)
+		err = rac.Config(ctx, group, object, index, item.Desired)
+		if err != nil {
+			return results, fmt.Errorf("failed to config %s (%w)", item.Key, err)
+		}
+		results = append(results, idrac.SetResult{Key: item.Key, Value: item.Desired})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,166 @@
+package idrac
+
+import (
+	"bufio"
+	"strings"
+)
+
+// Attribute is a single attribute (or legacy object) as reported by racadm
+// get or getconfig
+type Attribute struct {
+	Name         string `json:"name"`
+	Value        string `json:"value"`
+	PendingValue string `json:"pending_value,omitempty"`
+	Pending      bool   `json:"pending,omitempty"`
+	ReadOnly     bool   `json:"read_only,omitempty"`
+	WriteOnly    bool   `json:"write_only,omitempty"`
+}
+
+// AttributeGroup is a group of attributes that share a [Key=...] header
+// (e.g. [Key=iDRAC.Embedded.1#WebServer.1]). Key is split into the device
+// FQDD (iDRAC.Embedded.1) and the Group (WebServer.1).
+type AttributeGroup struct {
+	Key        string      `json:"key,omitempty"`
+	FQDD       string      `json:"fqdd,omitempty"`
+	Group      string      `json:"group,omitempty"`
+	Attributes []Attribute `json:"attributes"`
+}
+
+// AttributeTree is the hierarchical model of racadm get output: a list
+// of groups, each containing its attributes
+type AttributeTree struct {
+	Groups []AttributeGroup `json:"groups"`
+}
+
+// parseAttributeTree parses key=value output (as produced by racadm get and
+// getconfig) into an AttributeTree. Lines beginning with # are read only,
+// a trailing (Pending Value=...) is a pending value and a trailing
+// (Write-Only) marks a write only object (whose value is masked).
+func parseAttributeTree(output string) AttributeTree {
+	tree := AttributeTree{}
+
+	// group currently being filled
+	var group *AttributeGroup
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		// new group header
+		if strings.HasPrefix(line, "[Key=") && strings.HasSuffix(line, "]") {
+			key := strings.TrimSuffix(strings.TrimPrefix(line, "[Key="), "]")
+			fqdd, groupName, found := strings.Cut(key, "#")
+			if !found {
+				groupName = ""
+			}
+
+			tree.Groups = append(tree.Groups, AttributeGroup{
+				Key:   key,
+				FQDD:  fqdd,
+				Group: groupName,
+			})
+			group = &tree.Groups[len(tree.Groups)-1]
+			continue
+		}
+
+		// attribute lines
+		attr, ok := parseAttributeLine(line)
+		if !ok {
+			continue
+		}
+
+		// output without a header (e.g. getconfig) goes in an unnamed group
+		if group == nil {
+			tree.Groups = append(tree.Groups, AttributeGroup{})
+			group = &tree.Groups[len(tree.Groups)-1]
+		}
+		group.Attributes = append(group.Attributes, attr)
+	}
+
+	return tree
+}
+
+// parseAttributeLine parses a single Name=Value line. ok is false if the
+// line isn't an attribute.
+func parseAttributeLine(line string) (attr Attribute, ok bool) {
+	// read only marker
+	if strings.HasPrefix(line, "#") {
+		attr.ReadOnly = true
+		line = strings.TrimSpace(strings.TrimPrefix(line, "#"))
+	}
+
+	name, value, found := strings.Cut(line, "=")
+	if !found || name == "" || strings.ContainsAny(name, " \t") {
+		return Attribute{}, false
+	}
+	attr.Name = name
+
+	// write only marker
+	if strings.HasSuffix(value, "(Write-Only)") {
+		attr.WriteOnly = true
+		attr.ReadOnly = false
+		value = strings.TrimSpace(strings.TrimSuffix(value, "(Write-Only)"))
+	}
+
+	// pending value
+	if i := strings.LastIndex(value, "(Pending Value="); i >= 0 && strings.HasSuffix(value, ")") {
+		attr.Pending = true
+		attr.PendingValue = strings.TrimSuffix(value[i+len("(Pending Value="):], ")")
+		value = strings.TrimSpace(value[:i])
+	}
+
+	attr.Value = value
+
+	return attr, true
+}
+
+// Attribute returns the attribute that matches the dotted key (e.g.
+// iDRAC.Webserver.Timeout or iDRAC.Users.2.UserName). Matching is case
+// insensitive and a group without an index matches the first instance.
+// If the key is a bare attribute name, all groups are searched.
+func (tree AttributeTree) Attribute(key string) (Attribute, bool) {
+	parts := strings.Split(key, ".")
+	name := parts[len(parts)-1]
+
+	groupName := ""
+	if len(parts) > 2 {
+		groupName = strings.Join(parts[1:len(parts)-1], ".")
+	}
+
+	for _, group := range tree.Groups {
+		if groupName != "" && group.Group != "" &&
+			!strings.EqualFold(group.Group, groupName) &&
+			!strings.EqualFold(group.Group, groupName+".1") {
+			continue
+		}
+
+		for _, attr := range group.Attributes {
+			if strings.EqualFold(attr.Name, name) {
+				return attr, true
+			}
+		}
+	}
+
+	return Attribute{}, false
+}
+
+// Map returns the tree flattened to a map of Name to Value. Groups are
+// prefixed to names when the tree holds more than one group.
+func (tree AttributeTree) Map() map[string]string {
+	m := make(map[string]string)
+	for _, group := range tree.Groups {
+		prefix := ""
+		if len(tree.Groups) > 1 && group.Group != "" {
+			prefix = group.Group + "."
+		}
+
+		for _, attr := range group.Attributes {
+			m[prefix+attr.Name] = attr.Value
+		}
+	}
+
+	return m
+}
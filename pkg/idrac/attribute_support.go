@@ -0,0 +1,47 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+)
+
+// attributeProbeKey is read to check if the idrac supports get/set
+const attributeProbeKey = "iDRAC.Info.Version"
+
+// usesAttributes returns true if the idrac supports the get/set attribute
+// interface (iDRAC8/9, and iDRAC7 with 2.x firmware). Otherwise the legacy
+// getconfig/config interface must be used. Only a definitive answer (get
+// works, or the idrac answers that it failed) is cached; after any other
+// error (e.g. a timeout) false is returned and the next call checks again.
+func (rac *idrac) usesAttributes(ctx context.Context) bool {
+	if rac.supportsAttributes == 0 {
+		_, err := rac.Get(ctx, attributeProbeKey)
+		switch {
+		case err == nil:
+			rac.supportsAttributes = 1
+		case isCommandError(err) || errors.As(err, new(ReturnCode)):
+			rac.supportsAttributes = -1
+		default:
+			return false
+		}
+	}
+
+	return rac.supportsAttributes == 1
+}
+
+// verifyCredential confirms username and password can login to the idrac
+// by logging in (and out) with a new client
+func (rac *idrac) verifyCredential(username, password string) error {
+	verifyRac, err := NewIdrac(rac.hostname, username, password, rac.strictCerts)
+	if err != nil {
+		return err
+	}
+
+	_, err = verifyRac.Login()
+	if err != nil {
+		return err
+	}
+	_, _ = verifyRac.Logout()
+
+	return nil
+}
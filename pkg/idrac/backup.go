@@ -0,0 +1,343 @@
+package idrac
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BackupFormatVersion is the version of the backup archive layout written by
+// WriteBackup. ReadBackup refuses archives from a newer version.
+const BackupFormatVersion = 1
+
+// backup archive file names
+const (
+	backupManifestFile = "manifest.json"
+	backupSettingsFile = "settings.json"
+	backupUsersFile    = "users.json"
+	backupNetworkFile  = "network.json"
+	backupWebCertFile  = "webcert.pem"
+)
+
+// backupAttributeRoots are the get attribute roots that make up the idrac's
+// own configuration (host settings such as BIOS, NIC and RAID are in the
+// Server Configuration Profile instead)
+var backupAttributeRoots = []string{"iDRAC", "System", "LifecycleController"}
+
+// backupMaxDepth limits how deep get group listings are followed
+const backupMaxDepth = 2
+
+// BackupManifest describes a backup
+type BackupManifest struct {
+	FormatVersion   int       `json:"format_version"`
+	CreatedAt       time.Time `json:"created_at"`
+	Hostname        string    `json:"hostname"`
+	ServiceTag      string    `json:"service_tag,omitempty"`
+	FirmwareVersion string    `json:"firmware_version,omitempty"`
+	// Legacy is true if Settings are getconfig objects (cfgGroup.cfgObject
+	// or cfgGroup.index.cfgObject), false if they are get attributes
+	Legacy bool `json:"legacy"`
+	// WriteOnly are settings whose values can't be read (e.g. passwords),
+	// so aren't in the backup
+	WriteOnly []string `json:"write_only,omitempty"`
+	// Skipped are groups (or other parts of the backup) that couldn't be
+	// read, with the reason
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// Backup is a backup of an idrac's configuration. Settings holds every
+// readable, writable setting (so it can be replayed), keyed the same way as
+// Set or Config expect; user slots and network settings are in it too.
+// Users and Network are the same information in readable form and are
+// informational only (not replayed). Secrets (write-only settings) are never
+// included.
+type Backup struct {
+	Manifest       BackupManifest    `json:"manifest"`
+	Settings       map[string]string `json:"settings"`
+	Users          []User            `json:"users"`
+	Network        NICConfig         `json:"network"`
+	WebCertificate string            `json:"web_certificate,omitempty"`
+}
+
+// Backup reads the idrac's configuration: every readable get attribute (or
+// getconfig group on idracs without get), the web certificate, the users
+// (without passwords) and the network settings. Parts that can't be read are
+// recorded in the manifest rather than failing the backup. The commands'
+// output isn't logged.
+func (rac *idrac) Backup(ctx context.Context) (Backup, error) {
+	ctx = withQuietOutput(ctx)
+	backup := Backup{
+		Manifest: BackupManifest{
+			FormatVersion: BackupFormatVersion,
+			CreatedAt:     time.Now().UTC(),
+			Hostname:      rac.hostname,
+			Legacy:        !rac.usesAttributes(ctx),
+		},
+		Settings: make(map[string]string),
+	}
+	manifest := &backup.Manifest
+
+	version, err := rac.FirmwareVersion(ctx)
+	if err != nil {
+		manifest.Skipped = append(manifest.Skipped, fmt.Sprintf("firmware version: %s", err))
+	}
+	manifest.FirmwareVersion = version.Raw
+	serviceTag, err := rac.ServiceTag(ctx)
+	if err != nil {
+		manifest.Skipped = append(manifest.Skipped, fmt.Sprintf("service tag: %s", err))
+	}
+	manifest.ServiceTag = serviceTag
+
+	// settings
+	if manifest.Legacy {
+		err = rac.backupConfigGroups(ctx, &backup)
+		if err != nil {
+			return Backup{}, err
+		}
+	} else {
+		for _, root := range backupAttributeRoots {
+			rac.backupAttributes(ctx, root, root, backupMaxDepth, &backup)
+		}
+	}
+	if len(backup.Settings) == 0 {
+		return Backup{}, errors.New("backup failed, no settings could be read")
+	}
+	sort.Strings(manifest.WriteOnly)
+
+	// the rest
+	backup.Users, err = rac.Users(ctx)
+	if err != nil {
+		manifest.Skipped = append(manifest.Skipped, fmt.Sprintf("users: %s", err))
+	}
+	backup.Network, err = rac.GetNICConfig(ctx)
+	if err != nil {
+		manifest.Skipped = append(manifest.Skipped, fmt.Sprintf("network: %s", err))
+	}
+	backup.WebCertificate, err = rac.WebCertificate(ctx)
+	if err != nil {
+		manifest.Skipped = append(manifest.Skipped, fmt.Sprintf("web certificate: %s", err))
+	}
+
+	for _, skipped := range manifest.Skipped {
+		log.Printf("backup: skipped %s", skipped)
+	}
+
+	return backup, nil
+}
+
+// addSetting adds a setting read from the idrac to the backup, skipping read
+// only settings and recording (but not storing) write only ones
+func (backup *Backup) addSetting(key string, attr Attribute) {
+	switch {
+	case attr.ReadOnly:
+	case attr.WriteOnly:
+		backup.Manifest.WriteOnly = append(backup.Manifest.WriteOnly, key)
+	default:
+		backup.Settings[key] = attr.Value
+	}
+}
+
+// backupAttributes reads the attributes at key (whose first part is root).
+// get on a root or a group with instances lists the groups under it instead
+// of values (e.g. "iDRAC.Users.2 [Key=iDRAC.Embedded.1#Users.2]"); those are
+// followed up to depth levels.
+func (rac *idrac) backupAttributes(ctx context.Context, root, key string, depth int, backup *Backup) {
+	execResp, err := rac.get(ctx, []string{key})
+	if err != nil {
+		backup.Manifest.Skipped = append(backup.Manifest.Skipped, fmt.Sprintf("%s: %s", key, err))
+		return
+	}
+	output := execResp.Response.CommandOutput
+
+	// values
+	tree := parseAttributeTree(output)
+	if len(tree.Groups) > 0 {
+		for _, group := range tree.Groups {
+			prefix := key
+			if group.Group != "" {
+				prefix = root + "." + group.Group
+			}
+			for _, attr := range group.Attributes {
+				backup.addSetting(prefix+"."+attr.Name, attr)
+			}
+		}
+		return
+	}
+
+	// group listing
+	if depth == 0 {
+		return
+	}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		// the sub key is the first field, an instance's [Key=...] follows
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		subKey := fields[0]
+		if strings.HasPrefix(strings.ToLower(subKey), strings.ToLower(key)+".") && !strings.Contains(subKey, "=") {
+			rac.backupAttributes(ctx, root, subKey, depth-1, backup)
+		}
+	}
+}
+
+// backupConfigGroups reads every getconfig group. Groups with instances
+// (e.g. cfgUserAdmin) can't be read without an index, so those are read one
+// index at a time until an index fails.
+func (rac *idrac) backupConfigGroups(ctx context.Context, backup *Backup) error {
+	groups, err := rac.GetConfigGroups(ctx)
+	if err != nil {
+		return fmt.Errorf("backup failed, unable to list config groups (%w)", err)
+	}
+
+	addTree := func(group string, index int, tree AttributeTree) {
+		for _, attrGroup := range tree.Groups {
+			for _, attr := range attrGroup.Attributes {
+				key := fmt.Sprintf("%s.%s", group, attr.Name)
+				if index > 0 {
+					key = fmt.Sprintf("%s.%d.%s", group, index, attr.Name)
+				}
+				backup.addSetting(key, attr)
+			}
+		}
+	}
+
+	for _, group := range groups {
+		tree, err := rac.GetConfig(ctx, group, 0)
+		if err == nil {
+			addTree(group, 0, tree)
+			continue
+		}
+
+		// try as an indexed group
+		indexErr := err
+		for index := 1; index <= userLastSlot; index++ {
+			tree, err := rac.GetConfig(ctx, group, index)
+			if err != nil {
+				break
+			}
+			addTree(group, index, tree)
+			indexErr = nil
+		}
+		if indexErr != nil {
+			backup.Manifest.Skipped = append(backup.Manifest.Skipped, fmt.Sprintf("%s: %s", group, indexErr))
+		}
+	}
+
+	return nil
+}
+
+// WriteBackup writes the backup to w as a gzipped tar archive with a json
+// file per part (and the web certificate as pem)
+func WriteBackup(w io.Writer, backup Backup) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	writeFile := func(name string, data []byte) error {
+		err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: backup.Manifest.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	}
+	writeJSONFile := func(name string, v any) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeFile(name, append(data, '\n'))
+	}
+
+	// manifest first, so a reader can check the version before the rest
+	err := writeJSONFile(backupManifestFile, backup.Manifest)
+	if err == nil {
+		err = writeJSONFile(backupSettingsFile, backup.Settings)
+	}
+	if err == nil {
+		err = writeJSONFile(backupUsersFile, backup.Users)
+	}
+	if err == nil {
+		err = writeJSONFile(backupNetworkFile, backup.Network)
+	}
+	if err == nil && backup.WebCertificate != "" {
+		err = writeFile(backupWebCertFile, []byte(backup.WebCertificate))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write backup (%w)", err)
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ReadBackup reads a backup archive written by WriteBackup
+func ReadBackup(r io.Reader) (Backup, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return Backup{}, fmt.Errorf("not a backup archive (%w)", err)
+	}
+	defer gz.Close()
+
+	backup := Backup{}
+	haveManifest := false
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Backup{}, fmt.Errorf("invalid backup archive (%w)", err)
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return Backup{}, fmt.Errorf("invalid backup archive (%w)", err)
+		}
+
+		switch header.Name {
+		case backupManifestFile:
+			err = json.Unmarshal(data, &backup.Manifest)
+			if err == nil && backup.Manifest.FormatVersion > BackupFormatVersion {
+				return Backup{}, fmt.Errorf("backup format version %d is newer than this version supports (%d)", backup.Manifest.FormatVersion, BackupFormatVersion)
+			}
+			haveManifest = true
+		case backupSettingsFile:
+			err = json.Unmarshal(data, &backup.Settings)
+		case backupUsersFile:
+			err = json.Unmarshal(data, &backup.Users)
+		case backupNetworkFile:
+			err = json.Unmarshal(data, &backup.Network)
+		case backupWebCertFile:
+			backup.WebCertificate = string(data)
+		}
+		if err != nil {
+			return Backup{}, fmt.Errorf("invalid backup archive file %s (%w)", header.Name, err)
+		}
+	}
+
+	if !haveManifest || backup.Manifest.FormatVersion < 1 {
+		return Backup{}, errors.New("invalid backup archive (no manifest)")
+	}
+
+	return backup, nil
+}
@@ -0,0 +1,124 @@
+package idrac
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// BootDevice is a first boot device accepted by the idrac
+type BootDevice string
+
+// valid first boot devices (see cfgServerFirstBootDevice and
+// iDRAC.ServerBoot.FirstBootDevice in the RACADM CLI guides)
+const (
+	BootDeviceNormal              = BootDevice("Normal")
+	BootDevicePXE                 = BootDevice("PXE")
+	BootDeviceHDD                 = BootDevice("HDD")
+	BootDeviceCD                  = BootDevice("CD-DVD")
+	BootDeviceFloppy              = BootDevice("FDD")
+	BootDeviceSD                  = BootDevice("SD")
+	BootDeviceBIOSSetup           = BootDevice("BIOS")
+	BootDeviceVirtualCD           = BootDevice("VCD-DVD")
+	BootDeviceVirtualFD           = BootDevice("vFDD")
+	BootDeviceRemoteFileShare     = BootDevice("RFS")
+	BootDeviceLifecycleController = BootDevice("F10")
+	BootDeviceBootManager         = BootDevice("F11")
+)
+
+// bootDevices is every valid BootDevice
+var bootDevices = []BootDevice{
+	BootDeviceNormal, BootDevicePXE, BootDeviceHDD, BootDeviceCD, BootDeviceFloppy, BootDeviceSD,
+	BootDeviceBIOSSetup, BootDeviceVirtualCD, BootDeviceVirtualFD, BootDeviceRemoteFileShare,
+	BootDeviceLifecycleController, BootDeviceBootManager,
+}
+
+// ParseBootDevice returns the BootDevice matching name (case insensitive)
+func ParseBootDevice(name string) (BootDevice, error) {
+	for _, device := range bootDevices {
+		if strings.EqualFold(name, string(device)) {
+			return device, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid boot device %s", name)
+}
+
+// NextBoot is the idrac's first boot device configuration
+type NextBoot struct {
+	Device BootDevice `json:"device"`
+	Once   bool       `json:"once"`
+}
+
+// NextBoot returns the configured first boot device, read from
+// iDRAC.ServerBoot (iDRAC8/9) or cfgServerInfo (iDRAC6/7)
+func (rac *idrac) NextBoot(ctx context.Context) (NextBoot, error) {
+	var tree AttributeTree
+	var err error
+	deviceName := ""
+	onceName := ""
+
+	if rac.usesAttributes(ctx) {
+		tree, err = rac.Get(ctx, "iDRAC.ServerBoot")
+		deviceName, onceName = "FirstBootDevice", "BootOnce"
+	} else {
+		tree, err = rac.GetConfig(ctx, "cfgServerInfo", 0)
+		deviceName, onceName = "cfgServerFirstBootDevice", "cfgServerBootOnce"
+	}
+	if err != nil {
+		return NextBoot{}, err
+	}
+
+	device, _ := tree.Attribute(deviceName)
+	once, _ := tree.Attribute(onceName)
+
+	return NextBoot{
+		Device: BootDevice(device.Value),
+		Once:   once.Value == "1" || strings.EqualFold(once.Value, "enabled"),
+	}, nil
+}
+
+// SetNextBoot sets the first boot device. If once is true, the device is only
+// used for the next boot, after which the server returns to its normal boot
+// order. The server is not rebooted.
+func (rac *idrac) SetNextBoot(ctx context.Context, device BootDevice, once bool) error {
+	device, err := ParseBootDevice(string(device))
+	if err != nil {
+		return err
+	}
+
+	// boot once first, so a persistent device is never briefly set
+	if rac.usesAttributes(ctx) {
+		onceValue := "Disabled"
+		if once {
+			onceValue = "Enabled"
+		}
+		_, err = rac.Set(ctx, "iDRAC.ServerBoot.BootOnce", onceValue)
+		if err != nil {
+			return fmt.Errorf("failed to set boot once (%w)", err)
+		}
+
+		_, err = rac.Set(ctx, "iDRAC.ServerBoot.FirstBootDevice", string(device))
+		if err != nil {
+			return fmt.Errorf("failed to set first boot device (%w)", err)
+		}
+
+		return nil
+	}
+
+	onceValue := "0"
+	if once {
+		onceValue = "1"
+	}
+	err = rac.Config(ctx, "cfgServerInfo", "cfgServerBootOnce", 0, onceValue)
+	if err != nil {
+		return fmt.Errorf("failed to set boot once (%w)", err)
+	}
+
+	err = rac.Config(ctx, "cfgServerInfo", "cfgServerFirstBootDevice", 0, string(device))
+	if err != nil {
+		return fmt.Errorf("failed to set first boot device (%w)", err)
+	}
+
+	return nil
+}
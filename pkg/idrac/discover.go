@@ -53,6 +53,12 @@ func (rac *idrac) Discover() (discResp DiscoverResponse, err error) {
 		return DiscoverResponse{}, errBadResp
 	}
 
+	// save firmware version, if the endpoint version is one (four part)
+	version, err := ParseFirmwareVersion(discResp.Response.EndpointVersion)
+	if err == nil && strings.Count(version.Raw, ".") == 3 {
+		rac.firmwareVersion = version
+	}
+
 	// good response
 	return discResp, nil
 }
@@ -2,20 +2,71 @@ package idrac
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"errors"
 	"flag"
+	"fmt"
 	"io"
 	"log"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
 const endpointExec = "/cgi-bin/exec"
 
+// maxOutputLenLarge is the output length requested for subcommands whose
+// output grows with the server (e.g. logs and inventories), which the
+// default of 0x0fff would truncate
+const maxOutputLenLarge = "0xfffff"
+
 var (
 	errInvalidSubCommand      = errors.New("subcommand is either invalid or not implemented")
 	errInvalidOrMalpositioned = errors.New("invalid or malpositioned param or flag")
+
+	// ErrOutputTruncated is returned when a command's output filled the
+	// requested output length, so some of it was likely cut off
+	ErrOutputTruncated = errors.New("command output reached the maximum output length and was likely truncated")
 )
 
+// commandError is returned when the idrac ran a command and the command
+// failed (as opposed to the request failing); its message is the command's
+// output
+type commandError struct {
+	output string
+}
+
+// Error implements the error interface
+func (err *commandError) Error() string {
+	return err.output
+}
+
+// isCommandError returns true if err is (or wraps) a commandError
+func isCommandError(err error) bool {
+	cmdErr := &commandError{}
+	return errors.As(err, &cmdErr)
+}
+
+// quietOutputKey is the context key that marks commands whose output isn't
+// logged
+type quietOutputKey struct{}
+
+// withQuietOutput returns ctx with output logging off for the commands run
+// with it. Bulk exports (e.g. profiles, licenses, backups) use it so large
+// and sensitive output isn't dumped to the log.
+func withQuietOutput(ctx context.Context) context.Context {
+	return context.WithValue(ctx, quietOutputKey{}, true)
+}
+
+// isTransportError returns true if err is a failure to reach the idrac or
+// get its response (e.g. the connection was refused or dropped), rather
+// than an error the idrac returned
+func isTransportError(err error) bool {
+	urlErr := &url.Error{}
+	return errors.As(err, &urlErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
 // execPayload is the payload to execute on idrac
 type execPayload struct {
 	XMLName xml.Name `xml:"EXEC"`
@@ -44,16 +95,79 @@ type execResponse struct {
 // Exec executes the specified command against the idrac. To
 // avoid unexpected behavior, error if specified command has
 // not been specifically implemented and tested.
+// Destructive subcommands (see IsDestructive) are refused; use
+// ExecDestructive for those.
 func (rac *idrac) Exec(command string, flags []string) (execResp execResponse, err error) {
+	if IsDestructive(command) {
+		return execResponse{}, rac.errConfirmationRequired(command)
+	}
+
+	return rac.exec(context.Background(), command, flags)
+}
+
+// exec dispatches command to its subcommand implementation
+func (rac *idrac) exec(ctx context.Context, command string, flags []string) (execResp execResponse, err error) {
 	// check subcommand is implemented and parse flags accordingly
 	// subcommands:
 	// https://www.dell.com/support/manuals/en-us/poweredge-m630/idrac8_2.70.70.70_racadm/racadm-subcommand-details?guid=guid-cd4e81e6-818c-44fb-9e7a-82950425fbbb&lang=en-us
 	// https://www.dell.com/support/manuals/en-us/idrac9-lifecycle-controller-v5.x-series/idrac9_5.xx_racadm_pub/racadm-subcommand-details?guid=guid-3e09aba8-6e2c-4fd9-9a17-d05f2596dbac&lang=en-us
 	switch command {
+	case "clrsel":
+		execResp, err = rac.clrsel(ctx, flags)
+	case "closessn":
+		execResp, err = rac.closessn(ctx, flags)
+	case "config":
+		execResp, err = rac.config(ctx, flags)
+	case "fru":
+		execResp, err = rac.fru(ctx, flags)
+	case "fwupdate":
+		execResp, err = rac.fwupdate(ctx, flags)
+	case "get":
+		execResp, err = rac.get(ctx, flags)
+	case "getconfig":
+		execResp, err = rac.getconfig(ctx, flags)
+	case "getmacaddress":
+		execResp, err = rac.getmacaddress(ctx, flags)
+	case "getniccfg":
+		execResp, err = rac.getniccfg(ctx, flags)
+	case "getraclog":
+		execResp, err = rac.getraclog(ctx, flags)
+	case "getractime":
+		execResp, err = rac.getractime(ctx, flags)
+	case "getsel":
+		execResp, err = rac.getsel(ctx, flags)
+	case "getssninfo":
+		execResp, err = rac.getssninfo(ctx, flags)
+	case "getsvctag":
+		execResp, err = rac.getsvctag(ctx, flags)
+	case "getsysinfo":
+		execResp, err = rac.getsysinfo(ctx, flags)
+	case "getversion":
+		execResp, err = rac.getversion(ctx, flags)
+	case "hwinventory":
+		execResp, err = rac.hwinventory(ctx, flags)
+	case "jobqueue":
+		execResp, err = rac.jobqueue(ctx, flags)
+	case "lclog":
+		execResp, err = rac.lclog(ctx, flags)
+	case "license":
+		execResp, err = rac.license(ctx, flags)
+	case "raid":
+		execResp, err = rac.raid(ctx, flags)
 	case "racreset":
 		execResp, err = rac.racreset(flags)
 	case "racresetcfg":
-		execResp, err = rac.racresetcfg(flags)
+		execResp, err = rac.racresetcfg(ctx, flags)
+	case "remoteimage":
+		execResp, err = rac.remoteimage(ctx, flags)
+	case "serveraction":
+		execResp, err = rac.serveraction(ctx, flags)
+	case "set":
+		execResp, err = rac.set(ctx, flags)
+	case "setniccfg":
+		execResp, err = rac.setniccfg(ctx, flags)
+	case "setractime":
+		execResp, err = rac.setractime(ctx, flags)
 	case "sslcertdownload":
 		execResp, err = rac.sslcertdownload(flags)
 	case "sslcertupload":
@@ -61,7 +175,13 @@ func (rac *idrac) Exec(command string, flags []string) (execResp execResponse, e
 	case "sslkeyupload":
 		execResp, err = rac.sslkeyupload(flags)
 	case "sslresetcfg":
-		execResp, err = rac.sslresetcfg(flags)
+		execResp, err = rac.sslresetcfg(ctx, flags)
+	case "storage":
+		execResp, err = rac.storage(ctx, flags)
+	case "techsupreport":
+		execResp, err = rac.techsupreport(ctx, flags)
+	case "update":
+		execResp, err = rac.update(ctx, flags)
 	default:
 		// error, unsupported
 		return execResponse{}, errInvalidSubCommand
@@ -76,6 +196,11 @@ func (rac *idrac) Exec(command string, flags []string) (execResp execResponse, e
 // executePayload executes the specified payload against
 // the idrac and returns the response or an error.
 func (rac *idrac) executePayload(payload execPayload) (execResp execResponse, err error) {
+	return rac.executePayloadContext(context.Background(), payload)
+}
+
+// executePayloadContext is executePayload, but the request is bound to ctx
+func (rac *idrac) executePayloadContext(ctx context.Context, payload execPayload) (execResp execResponse, err error) {
 	// marshal payload
 	payloadXml, err := xml.Marshal(payload)
 	if err != nil {
@@ -83,7 +208,7 @@ func (rac *idrac) executePayload(payload execPayload) (execResp execResponse, er
 	}
 
 	// post
-	resp, err := rac.client.Post(rac.url()+endpointExec, "application/xml", bytes.NewBuffer(payloadXml))
+	resp, err := rac.client.PostContext(ctx, rac.url()+endpointExec, "application/xml", bytes.NewBuffer(payloadXml))
 	if err != nil {
 		return execResponse{}, err
 	}
@@ -106,19 +231,43 @@ func (rac *idrac) executePayload(payload execPayload) (execResp execResponse, er
 	}
 	// return command output as error if command errored
 	if execResp.Response.CommandReturnCode != RcOK {
-		return execResponse{}, errors.New(execResp.Response.CommandOutput)
+		return execResponse{}, &commandError{output: execResp.Response.CommandOutput}
 	}
 
-	// success - write command output
-	log.Printf("exec command output: %s", execResp.Response.CommandOutput)
+	// output that fills the requested length was cut off by the idrac,
+	// partial output is an error rather than silently incomplete
+	maxOutputLen, err := strconv.ParseUint(payload.Request.MaxOutputLen, 0, 64)
+	if err == nil && uint64(len(execResp.Response.CommandOutput)) >= maxOutputLen {
+		return execResponse{}, fmt.Errorf("%s: %w", payloadSubcommand(payload), ErrOutputTruncated)
+	}
+
+	// success - write command output (unless quiet)
+	if quiet, _ := ctx.Value(quietOutputKey{}).(bool); quiet {
+		log.Printf("exec command output: (%d bytes, not logged)", len(execResp.Response.CommandOutput))
+	} else {
+		log.Printf("exec command output: %s", execResp.Response.CommandOutput)
+	}
 
 	return execResp, nil
 }
 
+// payloadSubcommand returns the subcommand payload executes, without its
+// arguments (which may include secrets)
+func payloadSubcommand(payload execPayload) string {
+	fields := strings.Fields(payload.Request.CommandInput)
+	if len(fields) < 2 {
+		return payload.Request.CommandInput
+	}
+	return fields[1]
+}
+
 // parseFlags parses the flag set and returns an error if there are
 // any extraneous / leftover bits after the flags are parsed.
 func parseFlags(fs *flag.FlagSet, flags []string) (err error) {
-	fs.Parse(flags)
+	err = fs.Parse(flags)
+	if err != nil {
+		return err
+	}
 
 	// check for leftovers
 	if len(fs.Args()) > 0 {
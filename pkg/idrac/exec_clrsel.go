@@ -0,0 +1,43 @@
+package idrac
+
+import (
+	"context"
+	"flag"
+)
+
+// clrsel clears the System Event Log.
+// See clrsel in the iDRAC7, iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) clrsel(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	fs := flag.NewFlagSet("clrsel", flag.ExitOnError)
+
+	// no flags should be present
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = "racadm clrsel"
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// ClearSEL clears the System Event Log. Clearing is destructive, so it is
+// confirmed and snapshotted like ExecDestructive.
+func (rac *idrac) ClearSEL(ctx context.Context, confirm DestructiveConfirmation) error {
+	_, err := rac.ExecDestructive(ctx, "clrsel", nil, confirm)
+	return err
+}
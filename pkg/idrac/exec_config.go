@@ -0,0 +1,94 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// config executes the config subcommand to write a legacy (cfg*) object.
+// Usage: config -g group -o object [-i index] value
+// See config in the iDRAC7 and iDRAC8 RACADM CLI guides.
+func (rac *idrac) config(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	group := ""
+	object := ""
+	index := 0
+
+	fs := flag.NewFlagSet("config", flag.ExitOnError)
+	fs.StringVar(&group, "g", "", "group name (required)")
+	fs.StringVar(&object, "o", "", "object name (required)")
+	fs.IntVar(&index, "i", 0, "index of indexed group (e.g. cfgUserAdmin) (optional)")
+
+	// value is positional after the flags, so parseFlags can't be used (a
+	// value starting with - must follow --)
+	err = fs.Parse(flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+	if len(fs.Args()) == 0 {
+		return execResponse{}, errValueRequired
+	}
+	if len(fs.Args()) > 1 {
+		return execResponse{}, errInvalidOrMalpositioned
+	}
+	value := fs.Args()[0]
+
+	return rac.configObject(ctx, group, object, index, value)
+}
+
+// configObject validates and writes value to the legacy object. The command
+// is built directly (the value is never parsed as a flag, so values such as
+// "-5" are written as is).
+func (rac *idrac) configObject(ctx context.Context, group, object string, index int, value string) (execResp execResponse, err error) {
+	// validate command flags
+	if group == "" {
+		return execResponse{}, errors.New("group (-g) must be specified")
+	}
+	if object == "" {
+		return execResponse{}, errors.New("object (-o) must be specified")
+	}
+	if strings.ContainsAny(group+object, " \t\"") {
+		return execResponse{}, errors.New("invalid group or object name")
+	}
+	if index < 0 {
+		return execResponse{}, errors.New("index (-i) must be positive")
+	}
+	if strings.ContainsAny(value, "\"\r\n") {
+		return execResponse{}, errors.New("object value must not contain quotes or new lines")
+	}
+
+	// quote value if it contains whitespace (or is empty)
+	if value == "" || strings.ContainsAny(value, " \t") {
+		value = "\"" + value + "\""
+	}
+
+	// build payload to post to drac
+	indexParam := ""
+	if index != 0 {
+		indexParam = fmt.Sprintf(" -i %d", index)
+	}
+	cmdInput := fmt.Sprintf("racadm config -g %s -o %s%s %s", group, object, indexParam, value)
+
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// Config writes value to the legacy object in group (and index, if not 0).
+func (rac *idrac) Config(ctx context.Context, group, object string, index int, value string) error {
+	_, err := rac.configObject(ctx, group, object, index, value)
+	return err
+}
@@ -0,0 +1,125 @@
+package idrac
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"strings"
+)
+
+// FRU is a Field Replaceable Unit as reported by fru. Properties holds every
+// property as reported.
+type FRU struct {
+	Name         string            `json:"name,omitempty"`
+	Manufacturer string            `json:"manufacturer,omitempty"`
+	ProductName  string            `json:"product_name,omitempty"`
+	PartNumber   string            `json:"part_number,omitempty"`
+	SerialNumber string            `json:"serial_number,omitempty"`
+	AssetTag     string            `json:"asset_tag,omitempty"`
+	Properties   map[string]string `json:"properties"`
+}
+
+// fru executes the fru subcommand to display Field Replaceable Unit data.
+// See fru in the iDRAC RACADM CLI guides.
+func (rac *idrac) fru(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	fs := flag.NewFlagSet("fru", flag.ExitOnError)
+
+	// no flags currently supported
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = "racadm fru"
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// FRU returns the parsed Field Replaceable Unit data
+func (rac *idrac) FRU(ctx context.Context) ([]FRU, error) {
+	execResp, err := rac.fru(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseFRUs(execResp.Response.CommandOutput), nil
+}
+
+// ParseFRUs parses fru output. Units are separated by blank lines (or a
+// header line without a separator) and properties are "Key = Value" or
+// "Key : Value".
+func ParseFRUs(output string) []FRU {
+	frus := []FRU{}
+
+	var fru *FRU
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.Trim(line, "-") == "" {
+			fru = nil
+			continue
+		}
+
+		sep := "="
+		if !strings.Contains(line, "=") {
+			sep = ":"
+		}
+		key, value, found := strings.Cut(line, sep)
+
+		// a line without a separator names the next unit
+		if !found || (sep == ":" && strings.TrimSpace(value) == "") {
+			frus = append(frus, FRU{
+				Name:       strings.TrimSuffix(strings.TrimSuffix(line, ":"), " "),
+				Properties: make(map[string]string),
+			})
+			fru = &frus[len(frus)-1]
+			continue
+		}
+		if fru == nil {
+			frus = append(frus, FRU{Properties: make(map[string]string)})
+			fru = &frus[len(frus)-1]
+		}
+
+		key = strings.Join(strings.Fields(key), " ")
+		value = strings.TrimSpace(value)
+		fru.Properties[key] = value
+
+		lowerKey := strings.ToLower(key)
+		switch {
+		case strings.HasSuffix(lowerKey, "manufacturer"):
+			if fru.Manufacturer == "" {
+				fru.Manufacturer = value
+			}
+		case strings.HasSuffix(lowerKey, "product name") || lowerKey == "name":
+			if fru.ProductName == "" {
+				fru.ProductName = value
+			}
+		case strings.HasSuffix(lowerKey, "part number"):
+			if fru.PartNumber == "" {
+				fru.PartNumber = value
+			}
+		case strings.HasSuffix(lowerKey, "serial number"):
+			if fru.SerialNumber == "" {
+				fru.SerialNumber = value
+			}
+		case strings.HasSuffix(lowerKey, "asset tag"):
+			fru.AssetTag = value
+		}
+	}
+
+	return frus
+}
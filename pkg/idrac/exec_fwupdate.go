@@ -0,0 +1,140 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// fwupdateRemoteFile is the name the image is put on the idrac as
+const fwupdateRemoteFile = "firmimg.d7"
+
+var fwupdatePercentRegex = regexp.MustCompile(`(\d+)\s*%`)
+
+// FirmwareUpdateStatus is the parsed output of fwupdate -s
+type FirmwareUpdateStatus struct {
+	InProgress      bool   `json:"in_progress"`
+	Completed       bool   `json:"completed"`
+	Failed          bool   `json:"failed"`
+	PercentComplete int    `json:"percent_complete"`
+	Message         string `json:"message"`
+}
+
+// fwupdate executes the fwupdate subcommand (iDRAC7 and older) to update
+// the idrac firmware from a local image, or to show the update status.
+// Usage: fwupdate -p -u -d <local image file> | -s
+// See fwupdate in the iDRAC7 RACADM CLI guide.
+func (rac *idrac) fwupdate(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	put := false
+	update := false
+	imagePath := ""
+	status := false
+
+	fs := flag.NewFlagSet("fwupdate", flag.ExitOnError)
+	fs.BoolVar(&put, "p", false, "put the image on the idrac (requires -u and -d)")
+	fs.BoolVar(&update, "u", false, "perform the update (requires -p and -d)")
+	fs.StringVar(&imagePath, "d", "", "local firmware image file (e.g. firmimg.d7)")
+	fs.BoolVar(&status, "s", false, "display the update status")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		log.Println("-g, -a, and -r are not currently supported by goracadm")
+		return execResponse{}, err
+	}
+
+	// validate command flags and build command
+	cmdInput := ""
+	if status {
+		if put || update || imagePath != "" {
+			return execResponse{}, errors.New("-s can't be combined with other options")
+		}
+		cmdInput = "racadm fwupdate -s"
+	} else {
+		if !put || !update || imagePath == "" {
+			return execResponse{}, errors.New("-p, -u, and -d must be specified (or -s for status)")
+		}
+
+		content, err := os.ReadFile(imagePath)
+		if err != nil {
+			return execResponse{}, err
+		}
+
+		err = checkFirmwareImage(filepath.Base(imagePath), content, true)
+		if err != nil {
+			return execResponse{}, err
+		}
+
+		// put the image on the rac
+		err = rac.putfileContext(ctx, putfilePayload{
+			filename: fwupdateRemoteFile,
+			flags:    0,
+			content:  content,
+			binary:   true,
+		})
+		if err != nil {
+			return execResponse{}, err
+		}
+
+		cmdInput = "racadm fwupdate -p -u -d " + fwupdateRemoteFile
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// ParseFirmwareUpdateStatus parses fwupdate -s output, e.g.
+// "Firmware update in progress [40% complete]"
+func ParseFirmwareUpdateStatus(output string) FirmwareUpdateStatus {
+	status := FirmwareUpdateStatus{
+		Message: strings.TrimSpace(output),
+	}
+	lowerOutput := strings.ToLower(status.Message)
+
+	switch {
+	case strings.Contains(lowerOutput, "fail") || strings.Contains(lowerOutput, "error"):
+		status.Failed = true
+	case strings.Contains(lowerOutput, "completed successfully"):
+		status.Completed = true
+		status.PercentComplete = 100
+	case strings.Contains(lowerOutput, "in progress") || strings.Contains(lowerOutput, "upload") ||
+		strings.Contains(lowerOutput, "writing") || strings.Contains(lowerOutput, "verif"):
+		status.InProgress = true
+	}
+
+	match := fwupdatePercentRegex.FindStringSubmatch(status.Message)
+	if match != nil {
+		status.PercentComplete, _ = strconv.Atoi(match[1])
+	}
+
+	return status
+}
+
+// FirmwareUpdateStatus returns the status of an fwupdate (iDRAC7 and older)
+func (rac *idrac) FirmwareUpdateStatus(ctx context.Context) (FirmwareUpdateStatus, error) {
+	execResp, err := rac.fwupdate(ctx, []string{"-s"})
+	if err != nil {
+		return FirmwareUpdateStatus{}, err
+	}
+
+	return ParseFirmwareUpdateStatus(execResp.Response.CommandOutput), nil
+}
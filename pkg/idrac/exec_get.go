@@ -0,0 +1,97 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+var errKeyRequired = errors.New("attribute key must be specified")
+
+// get executes the get subcommand to read attributes by dotted key
+// (e.g. iDRAC.Webserver.Timeout or BIOS.SysProfileSettings), or with -f and
+// -t to export a Server Configuration Profile (to a local file, or a network
+// share with -l).
+// Usage: get <key> | get -f <file> -t xml|json [-l share [-u user] [-p password]]
+// See get in the iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) get(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// key (if any) is positional and comes before any flags
+	key := ""
+	if len(flags) > 0 && !strings.HasPrefix(flags[0], "-") {
+		key = flags[0]
+		flags = flags[1:]
+	}
+
+	// parse command flags (options)
+	file := ""
+	format := ""
+	share := NetworkShare{}
+
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	fs.StringVar(&file, "f", "", "server configuration profile file name (export)")
+	fs.StringVar(&format, "t", "", "server configuration profile format, xml or json (export)")
+	fs.StringVar(&share.Path, "l", "", "network share to export to, e.g. //server/share or server:/path (optional)")
+	fs.StringVar(&share.Username, "u", "", "network share username (optional)")
+	fs.StringVar(&share.Password, "p", "", "network share password (optional)")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// profile export
+	if file != "" || format != "" {
+		if key != "" {
+			return execResponse{}, errInvalidOrMalpositioned
+		}
+		return rac.getSCP(ctx, file, SCPFormat(format), share)
+	}
+	if share != (NetworkShare{}) {
+		return execResponse{}, errors.New("-l, -u, and -p are only valid with -f and -t")
+	}
+
+	// validate key
+	if key == "" {
+		return execResponse{}, errKeyRequired
+	}
+	if strings.ContainsAny(key, " \t\"") {
+		return execResponse{}, fmt.Errorf("invalid attribute key (%s)", key)
+	}
+
+	// build payload to post to drac
+	cmdInput := fmt.Sprintf("racadm get %s", key)
+
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// Get reads the attribute(s) at the dotted key and returns them parsed into
+// an AttributeTree. Requires iDRAC8 or newer (iDRAC7 with 2.x firmware also
+// supports get).
+func (rac *idrac) Get(ctx context.Context, key string) (AttributeTree, error) {
+	// a leading - would be parsed as a flag
+	if strings.HasPrefix(key, "-") {
+		return AttributeTree{}, fmt.Errorf("invalid attribute key (%s)", key)
+	}
+
+	execResp, err := rac.get(ctx, []string{key})
+	if err != nil {
+		return AttributeTree{}, err
+	}
+
+	return parseAttributeTree(execResp.Response.CommandOutput), nil
+}
@@ -0,0 +1,121 @@
+package idrac
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// getconfig executes the getconfig subcommand to read legacy (cfg*) groups
+// and objects. This is the only configuration interface on iDRAC6 and on
+// iDRAC7 with 1.x firmware.
+// See getconfig in the iDRAC7 and iDRAC8 RACADM CLI guides.
+func (rac *idrac) getconfig(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	group := ""
+	object := ""
+	index := 0
+	listGroups := false
+
+	fs := flag.NewFlagSet("getconfig", flag.ExitOnError)
+	fs.StringVar(&group, "g", "", "group name (required unless -h)")
+	fs.StringVar(&object, "o", "", "object name (optional)")
+	fs.IntVar(&index, "i", 0, "index of indexed group (e.g. cfgUserAdmin) (optional)")
+	fs.BoolVar(&listGroups, "h", false, "list available groups")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags and build command
+	cmdInput := ""
+	if listGroups {
+		if group != "" || object != "" || index != 0 {
+			return execResponse{}, errors.New("-h can't be combined with other options")
+		}
+		cmdInput = "racadm getconfig -h"
+	} else {
+		if group == "" {
+			return execResponse{}, errors.New("group (-g) must be specified")
+		}
+		if strings.ContainsAny(group+object, " \t\"") {
+			return execResponse{}, errors.New("invalid group or object name")
+		}
+		if index < 0 {
+			return execResponse{}, errors.New("index (-i) must be positive")
+		}
+
+		cmdInput = fmt.Sprintf("racadm getconfig -g %s", group)
+		if object != "" {
+			cmdInput += fmt.Sprintf(" -o %s", object)
+		}
+		if index != 0 {
+			cmdInput += fmt.Sprintf(" -i %d", index)
+		}
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// GetConfig reads the legacy group (and index, if not 0) and returns its
+// objects parsed into an AttributeTree with a single group.
+func (rac *idrac) GetConfig(ctx context.Context, group string, index int) (AttributeTree, error) {
+	flags := []string{"-g", group}
+	if index != 0 {
+		flags = append(flags, "-i", fmt.Sprint(index))
+	}
+
+	execResp, err := rac.getconfig(ctx, flags)
+	if err != nil {
+		return AttributeTree{}, err
+	}
+
+	tree := parseAttributeTree(execResp.Response.CommandOutput)
+
+	// getconfig output has no header, name the group after what was requested
+	for i := range tree.Groups {
+		tree.Groups[i].Group = group
+		if index != 0 {
+			tree.Groups[i].Group = fmt.Sprintf("%s.%d", group, index)
+		}
+	}
+
+	return tree, nil
+}
+
+// GetConfigGroups returns the names of the legacy groups the idrac supports
+func (rac *idrac) GetConfigGroups(ctx context.Context) ([]string, error) {
+	execResp, err := rac.getconfig(ctx, []string{"-h"})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []string{}
+	scanner := bufio.NewScanner(strings.NewReader(execResp.Response.CommandOutput))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "cfg") && !strings.ContainsAny(line, " \t=") {
+			groups = append(groups, line)
+		}
+	}
+
+	return groups, nil
+}
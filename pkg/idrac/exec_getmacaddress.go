@@ -0,0 +1,115 @@
+package idrac
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"regexp"
+	"strings"
+)
+
+var macAddressRegex = regexp.MustCompile(`^([0-9A-Fa-f]{2}[:-]){5}[0-9A-Fa-f]{2}$`)
+
+// ModuleMACAddresses are the MAC addresses of one chassis module
+type ModuleMACAddresses struct {
+	Name         string   `json:"name"`
+	Presence     string   `json:"presence,omitempty"`
+	MACAddresses []string `json:"mac_addresses"`
+}
+
+// getmacaddress executes the getmacaddress subcommand to display the MAC
+// addresses of chassis modules. This is only supported by chassis (CMC)
+// controllers.
+// See getmacaddress in the CMC RACADM CLI guides.
+func (rac *idrac) getmacaddress(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	module := ""
+	extended := false
+	all := false
+
+	fs := flag.NewFlagSet("getmacaddress", flag.ExitOnError)
+	fs.StringVar(&module, "m", "", "module, e.g. chassis, server-<n>, or switch-<n> (optional)")
+	fs.BoolVar(&extended, "t", false, "display extended (iSCSI/FCoE) mac addresses")
+	fs.BoolVar(&all, "a", false, "display mac addresses of all servers and partitions")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags and build command
+	if strings.ContainsAny(module, " \t\"") {
+		return execResponse{}, errInvalidModule
+	}
+	if all && module != "" {
+		return execResponse{}, errors.New("-a can't be combined with -m")
+	}
+
+	cmdInput := "racadm getmacaddress"
+	if module != "" {
+		cmdInput += " -m " + module
+	}
+	if extended {
+		cmdInput += " -t"
+	}
+	if all {
+		cmdInput += " -a"
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// ChassisMACAddresses returns the MAC addresses of every chassis module
+func (rac *idrac) ChassisMACAddresses(ctx context.Context) ([]ModuleMACAddresses, error) {
+	execResp, err := rac.getmacaddress(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseModuleMACAddresses(execResp.Response.CommandOutput), nil
+}
+
+// ParseModuleMACAddresses parses the getmacaddress table. Each row is a
+// module name, its presence and then its MAC addresses; the header row (and
+// any row without a MAC address) is skipped.
+func ParseModuleMACAddresses(output string) []ModuleMACAddresses {
+	modules := []ModuleMACAddresses{}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "<") {
+			continue
+		}
+
+		module := ModuleMACAddresses{Name: fields[0]}
+		for _, field := range fields[1:] {
+			if macAddressRegex.MatchString(field) {
+				module.MACAddresses = append(module.MACAddresses, field)
+			} else if module.Presence == "" && len(module.MACAddresses) == 0 {
+				module.Presence = field
+			}
+		}
+
+		if len(module.MACAddresses) > 0 {
+			modules = append(modules, module)
+		}
+	}
+
+	return modules
+}
@@ -0,0 +1,105 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+// getraclog executes the getraclog subcommand to read the RAC log using the
+// specified flags.
+// See getraclog in the iDRAC7, iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) getraclog(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	numRecords := false
+	start := 0
+	count := 0
+	noHeaders := false
+	oneLine := false
+
+	fs := flag.NewFlagSet("getraclog", flag.ExitOnError)
+	fs.BoolVar(&numRecords, "i", false, "display the number of records in the RAC log")
+	fs.IntVar(&start, "s", 0, "record to start displaying from (optional)")
+	fs.IntVar(&count, "c", 0, "maximum number of records to display (optional)")
+	fs.BoolVar(&noHeaders, "A", false, "do not print headers or labels")
+	fs.BoolVar(&oneLine, "o", false, "display each record on a single line")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags and build command
+	if start < 0 || count < 0 {
+		return execResponse{}, errors.New("start (-s) and count (-c) must be positive")
+	}
+	if numRecords && (start != 0 || count != 0 || noHeaders || oneLine) {
+		return execResponse{}, errors.New("-i can't be combined with other options")
+	}
+
+	cmdInput := "racadm getraclog"
+	if numRecords {
+		cmdInput += " -i"
+	}
+	if start != 0 {
+		cmdInput += fmt.Sprintf(" -s %d", start)
+	}
+	if count != 0 {
+		cmdInput += fmt.Sprintf(" -c %d", count)
+	}
+	if noHeaders {
+		cmdInput += " -A"
+	}
+	if oneLine {
+		cmdInput += " -o"
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = maxOutputLenLarge
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// GetRACLog returns count (all, if 0) RAC log records beginning with record
+// start (first, if 0)
+func (rac *idrac) GetRACLog(ctx context.Context, start, count int) ([]LogRecord, error) {
+	flags := []string{}
+	if start != 0 {
+		flags = append(flags, "-s", strconv.Itoa(start))
+	}
+	if count != 0 {
+		flags = append(flags, "-c", strconv.Itoa(count))
+	}
+
+	execResp, err := rac.getraclog(ctx, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseLogRecords(execResp.Response.CommandOutput), nil
+}
+
+// RACLogSince returns the RAC log records newer than cursor and the cursor
+// advanced past them. A zero cursor returns the whole log.
+func (rac *idrac) RACLogSince(ctx context.Context, cursor LogCursor) ([]LogRecord, LogCursor, error) {
+	records, err := rac.GetRACLog(ctx, 0, 0)
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	records = cursor.newRecords(records)
+	return records, cursor.Advance(records), nil
+}
@@ -0,0 +1,271 @@
+package idrac
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SELSeverity is the severity of a System Event Log record
+type SELSeverity string
+
+// known SEL severities
+const (
+	SELSeverityOk             = SELSeverity("Ok")
+	SELSeverityWarning        = SELSeverity("Warning")
+	SELSeverityCritical       = SELSeverity("Critical")
+	SELSeverityNonRecoverable = SELSeverity("Non-Recoverable")
+	SELSeverityUnknown        = SELSeverity("Unknown")
+)
+
+// rank orders severities from least (0) to most severe. Unknown severities
+// rank as warning so they aren't dropped by a severity filter.
+func (sev SELSeverity) rank() int {
+	switch strings.ToLower(string(sev)) {
+	case "ok", "informational", "info":
+		return 0
+	case "critical":
+		return 2
+	case "non-recoverable", "nonrecoverable":
+		return 3
+	}
+
+	return 1
+}
+
+// SELRecord is a single System Event Log record
+type SELRecord struct {
+	Record      int         `json:"record"`
+	Timestamp   time.Time   `json:"timestamp"`
+	DateTime    string      `json:"date_time"`
+	Source      string      `json:"source,omitempty"`
+	Sensor      string      `json:"sensor,omitempty"`
+	Severity    SELSeverity `json:"severity"`
+	Description string      `json:"description"`
+	RawData     string      `json:"raw_data,omitempty"`
+}
+
+// selTimeLayouts are the Date/Time formats used by the various idrac versions
+var selTimeLayouts = []string{
+	"01/02/2006 15:04:05",
+	"Mon Jan 2 2006 15:04:05",
+	"Mon Jan _2 2006 15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// getsel executes the getsel subcommand to read the System Event Log using
+// the specified flags.
+// See getsel in the iDRAC7, iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) getsel(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	numRecords := false
+	start := 0
+	count := 0
+	extended := false
+	raw := false
+	noHeaders := false
+	oneLine := false
+
+	fs := flag.NewFlagSet("getsel", flag.ExitOnError)
+	fs.BoolVar(&numRecords, "i", false, "display the number of records in the SEL")
+	fs.IntVar(&start, "s", 0, "record to start displaying from (optional)")
+	fs.IntVar(&count, "c", 0, "maximum number of records to display (optional)")
+	fs.BoolVar(&extended, "E", false, "display the raw SEL data with each record")
+	fs.BoolVar(&raw, "R", false, "display only the raw SEL data")
+	fs.BoolVar(&noHeaders, "A", false, "do not print headers or labels")
+	fs.BoolVar(&oneLine, "o", false, "display each record on a single line")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags and build command
+	if start < 0 || count < 0 {
+		return execResponse{}, errors.New("start (-s) and count (-c) must be positive")
+	}
+	if numRecords && (start != 0 || count != 0 || extended || raw || noHeaders || oneLine) {
+		return execResponse{}, errors.New("-i can't be combined with other options")
+	}
+
+	cmdInput := "racadm getsel"
+	if numRecords {
+		cmdInput += " -i"
+	}
+	if start != 0 {
+		cmdInput += fmt.Sprintf(" -s %d", start)
+	}
+	if count != 0 {
+		cmdInput += fmt.Sprintf(" -c %d", count)
+	}
+	for _, f := range []struct {
+		set   bool
+		param string
+	}{
+		{extended, " -E"},
+		{raw, " -R"},
+		{noHeaders, " -A"},
+		{oneLine, " -o"},
+	} {
+		if f.set {
+			cmdInput += f.param
+		}
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = maxOutputLenLarge
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// SELCount returns the number of records in the System Event Log
+func (rac *idrac) SELCount(ctx context.Context) (int, error) {
+	execResp, err := rac.getsel(ctx, []string{"-i"})
+	if err != nil {
+		return 0, err
+	}
+
+	// e.g. Total Records: 23
+	fields := strings.Fields(execResp.Response.CommandOutput)
+	if len(fields) == 0 {
+		return 0, errors.New("empty getsel -i output")
+	}
+
+	return strconv.Atoi(fields[len(fields)-1])
+}
+
+// GetSEL returns count (all, if 0) System Event Log records beginning with
+// record start (first, if 0). The raw SEL data of each record is included.
+func (rac *idrac) GetSEL(ctx context.Context, start, count int) ([]SELRecord, error) {
+	flags := []string{"-E"}
+	if start != 0 {
+		flags = append(flags, "-s", strconv.Itoa(start))
+	}
+	if count != 0 {
+		flags = append(flags, "-c", strconv.Itoa(count))
+	}
+
+	execResp, err := rac.getsel(ctx, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseSELRecords(execResp.Response.CommandOutput), nil
+}
+
+// ParseSELRecords parses getsel output (with or without -E) into records.
+// Records are made of "Key: Value" lines and separated by dashed lines.
+func ParseSELRecords(output string) []SELRecord {
+	records := []SELRecord{}
+
+	var record *SELRecord
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// separator ends the current record
+		if line == "" || strings.Trim(line, "-") == "" {
+			record = nil
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			// continuation of a multi line description
+			if record != nil {
+				record.Description += " " + line
+			}
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "record":
+			records = append(records, SELRecord{})
+			record = &records[len(records)-1]
+			record.Record, _ = strconv.Atoi(value)
+			continue
+		}
+
+		// anything else needs a record
+		if record == nil {
+			continue
+		}
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "date/time":
+			record.DateTime = value
+			for _, layout := range selTimeLayouts {
+				t, err := time.ParseInLocation(layout, value, time.Local)
+				if err == nil {
+					record.Timestamp = t
+					break
+				}
+			}
+		case "source":
+			record.Source = value
+		case "sensor", "sensor type":
+			record.Sensor = value
+		case "severity":
+			record.Severity = SELSeverity(value)
+		case "description":
+			record.Description = value
+		case "raw data", "raw":
+			record.RawData = value
+		default:
+			// description text may contain a colon
+			if record.Description != "" {
+				record.Description += " " + line
+			}
+		}
+	}
+
+	return records
+}
+
+// SELFilter selects SEL records. Zero values don't filter. Records whose
+// Date/Time couldn't be parsed (zero Timestamp) are never dropped by Since
+// or Until, so an unknown time format can't hide records.
+type SELFilter struct {
+	MinSeverity SELSeverity
+	Since       time.Time
+	Until       time.Time
+}
+
+// FilterSELRecords returns the records that match filter
+func FilterSELRecords(records []SELRecord, filter SELFilter) []SELRecord {
+	filtered := []SELRecord{}
+	for _, record := range records {
+		if filter.MinSeverity != "" && record.Severity.rank() < filter.MinSeverity.rank() {
+			continue
+		}
+		if !record.Timestamp.IsZero() {
+			if !filter.Since.IsZero() && record.Timestamp.Before(filter.Since) {
+				continue
+			}
+			if !filter.Until.IsZero() && record.Timestamp.After(filter.Until) {
+				continue
+			}
+		}
+
+		filtered = append(filtered, record)
+	}
+
+	return filtered
+}
@@ -0,0 +1,123 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// getsvctag executes the getsvctag subcommand to display the service tag.
+// See getsvctag in the iDRAC7, iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) getsvctag(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	module := ""
+
+	fs := flag.NewFlagSet("getsvctag", flag.ExitOnError)
+	fs.StringVar(&module, "m", "", "module, e.g. chassis or server-<n> (chassis only) (optional)")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags and build command
+	if strings.ContainsAny(module, " \t\"") {
+		return execResponse{}, errInvalidModule
+	}
+
+	cmdInput := "racadm getsvctag"
+	if module != "" {
+		cmdInput += " -m " + module
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// ServiceTag returns the server's service tag
+func (rac *idrac) ServiceTag(ctx context.Context) (string, error) {
+	execResp, err := rac.getsvctag(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// output is only the tag, but tolerate a label (e.g. Service Tag = ABC1234)
+	output := strings.TrimSpace(execResp.Response.CommandOutput)
+	if _, tag, found := strings.Cut(output, "="); found {
+		output = strings.TrimSpace(tag)
+	}
+	if output == "" || strings.ContainsAny(output, " \t\n") {
+		return "", fmt.Errorf("unexpected getsvctag output (%s)", output)
+	}
+
+	return output, nil
+}
+
+// AssetInfo identifies the physical server behind an idrac
+type AssetInfo struct {
+	ServiceTag   string       `json:"service_tag"`
+	AssetTag     string       `json:"asset_tag,omitempty"`
+	Manufacturer string       `json:"manufacturer,omitempty"`
+	Model        string       `json:"model,omitempty"`
+	SerialNumber string       `json:"serial_number,omitempty"`
+	IDRACMAC     string       `json:"idrac_mac_address,omitempty"`
+	NICs         []NICAddress `json:"nics,omitempty"`
+}
+
+// AssetInfo gathers the server's identifying information from getsysinfo,
+// getsvctag and fru. fru isn't available on every idrac, so its failure
+// isn't an error.
+func (rac *idrac) AssetInfo(ctx context.Context) (AssetInfo, error) {
+	info, err := rac.GetSysInfo(ctx)
+	if err != nil {
+		return AssetInfo{}, err
+	}
+
+	asset := AssetInfo{
+		ServiceTag: info.System.ServiceTag,
+		Model:      info.System.Model,
+		IDRACMAC:   info.RAC.MACAddress,
+		NICs:       info.NICs,
+	}
+	if asset.ServiceTag == "" {
+		asset.ServiceTag, err = rac.ServiceTag(ctx)
+		if err != nil {
+			return AssetInfo{}, err
+		}
+	}
+	if asset.ServiceTag == "" {
+		return AssetInfo{}, errors.New("service tag not reported by idrac")
+	}
+
+	frus, err := rac.FRU(ctx)
+	if err == nil {
+		for _, fru := range frus {
+			if asset.Manufacturer == "" {
+				asset.Manufacturer = fru.Manufacturer
+			}
+			if asset.AssetTag == "" {
+				asset.AssetTag = fru.AssetTag
+			}
+			if asset.SerialNumber == "" {
+				asset.SerialNumber = fru.SerialNumber
+			}
+		}
+	}
+
+	return asset, nil
+}
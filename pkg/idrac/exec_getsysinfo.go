@@ -0,0 +1,235 @@
+package idrac
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"strings"
+)
+
+// SystemInfo is the parsed output of getsysinfo. Values the idrac didn't
+// report are left empty. Sections holds every key/value pair as reported,
+// by section, so fields not mapped to the struct are still available.
+type SystemInfo struct {
+	RAC      RACInfo                      `json:"rac"`
+	System   SystemDetails                `json:"system"`
+	NICs     []NICAddress                 `json:"embedded_nics,omitempty"`
+	Sections map[string]map[string]string `json:"sections"`
+}
+
+// RACInfo is the RAC Information (and its network) section of getsysinfo
+type RACInfo struct {
+	DateTime           string `json:"date_time,omitempty"`
+	FirmwareVersion    string `json:"firmware_version,omitempty"`
+	FirmwareBuild      string `json:"firmware_build,omitempty"`
+	LastFirmwareUpdate string `json:"last_firmware_update,omitempty"`
+	HardwareVersion    string `json:"hardware_version,omitempty"`
+	MACAddress         string `json:"mac_address,omitempty"`
+	DNSRACName         string `json:"dns_rac_name,omitempty"`
+	DNSDomain          string `json:"dns_domain,omitempty"`
+	IPv4Address        string `json:"ipv4_address,omitempty"`
+	IPv4Gateway        string `json:"ipv4_gateway,omitempty"`
+	IPv4Netmask        string `json:"ipv4_netmask,omitempty"`
+	IPv4DHCPEnabled    bool   `json:"ipv4_dhcp_enabled"`
+}
+
+// SystemDetails is the System Information section of getsysinfo
+type SystemDetails struct {
+	Model              string `json:"model,omitempty"`
+	Revision           string `json:"revision,omitempty"`
+	BIOSVersion        string `json:"bios_version,omitempty"`
+	ServiceTag         string `json:"service_tag,omitempty"`
+	ExpressServiceCode string `json:"express_service_code,omitempty"`
+	HostName           string `json:"host_name,omitempty"`
+	OSName             string `json:"os_name,omitempty"`
+	OSVersion          string `json:"os_version,omitempty"`
+	PowerStatus        string `json:"power_status,omitempty"`
+}
+
+// NICAddress is one line of the Embedded NIC MAC Addresses section
+// (e.g. NIC.Embedded.1-1-1 Ethernet = 18:66:DA:00:00:00 or, on iDRAC6,
+// NIC1 Ethernet = 00:00:00:00:00:00)
+type NICAddress struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	MACAddress string `json:"mac_address"`
+}
+
+// getsysinfo executes the getsysinfo subcommand using the specified flags.
+// See getsysinfo in the iDRAC7, iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) getsysinfo(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	racInfo := false
+	sysInfo := false
+	watchdog := false
+	common := false
+	ipv4 := false
+	ipv6 := false
+	noHeaders := false
+
+	fs := flag.NewFlagSet("getsysinfo", flag.ExitOnError)
+	fs.BoolVar(&racInfo, "d", false, "display idrac information")
+	fs.BoolVar(&sysInfo, "s", false, "display system information")
+	fs.BoolVar(&watchdog, "w", false, "display watchdog information")
+	fs.BoolVar(&common, "c", false, "display common settings")
+	fs.BoolVar(&ipv4, "4", false, "display ipv4 settings")
+	fs.BoolVar(&ipv6, "6", false, "display ipv6 settings")
+	fs.BoolVar(&noHeaders, "A", false, "do not print headers or labels")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// build command
+	cmdInput := "racadm getsysinfo"
+	for _, f := range []struct {
+		set   bool
+		param string
+	}{
+		{racInfo, " -d"},
+		{sysInfo, " -s"},
+		{watchdog, " -w"},
+		{common, " -c"},
+		{ipv4, " -4"},
+		{ipv6, " -6"},
+		{noHeaders, " -A"},
+	} {
+		if f.set {
+			cmdInput += f.param
+		}
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = maxOutputLenLarge
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// GetSysInfo executes getsysinfo and returns the parsed SystemInfo
+func (rac *idrac) GetSysInfo(ctx context.Context) (SystemInfo, error) {
+	execResp, err := rac.getsysinfo(ctx, nil)
+	if err != nil {
+		return SystemInfo{}, err
+	}
+
+	return ParseSystemInfo(execResp.Response.CommandOutput), nil
+}
+
+// ParseSystemInfo parses getsysinfo output. It tolerates the differences
+// between iDRAC6 through iDRAC9 (section names, key names and alignment).
+func ParseSystemInfo(output string) SystemInfo {
+	info := SystemInfo{
+		Sections: make(map[string]map[string]string),
+	}
+
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		// section header (e.g. "RAC Information:")
+		if strings.HasSuffix(line, ":") && !strings.Contains(line, "=") {
+			section = strings.TrimSuffix(line, ":")
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key = strings.Join(strings.Fields(key), " ")
+		value = strings.TrimSpace(value)
+
+		if info.Sections[section] == nil {
+			info.Sections[section] = make(map[string]string)
+		}
+		info.Sections[section][key] = value
+
+		// nic lines are "<name> <type> = <mac>"
+		if strings.Contains(strings.ToLower(section), "nic") {
+			name, nicType, _ := strings.Cut(key, " ")
+			info.NICs = append(info.NICs, NICAddress{
+				Name:       name,
+				Type:       nicType,
+				MACAddress: value,
+			})
+			continue
+		}
+
+		info.setField(section, key, value)
+	}
+
+	return info
+}
+
+// setField maps a getsysinfo key to the corresponding struct field. Keys are
+// unique across sections except for the IPv4 and IPv6 settings, so IPv6 is
+// skipped.
+func (info *SystemInfo) setField(section, key, value string) {
+	if strings.Contains(strings.ToLower(section), "ipv6") {
+		return
+	}
+
+	switch strings.ToLower(key) {
+	// rac
+	case "rac date/time":
+		info.RAC.DateTime = value
+	case "firmware version":
+		info.RAC.FirmwareVersion = value
+	case "firmware build":
+		info.RAC.FirmwareBuild = value
+	case "last firmware update":
+		info.RAC.LastFirmwareUpdate = value
+	case "hardware version":
+		info.RAC.HardwareVersion = value
+	case "mac address":
+		info.RAC.MACAddress = value
+	case "dns rac name":
+		info.RAC.DNSRACName = value
+	case "current dns domain":
+		info.RAC.DNSDomain = value
+	case "current ip address":
+		info.RAC.IPv4Address = value
+	case "current ip gateway":
+		info.RAC.IPv4Gateway = value
+	case "current ip netmask":
+		info.RAC.IPv4Netmask = value
+	case "dhcp enabled":
+		info.RAC.IPv4DHCPEnabled = value == "1" || strings.EqualFold(value, "enabled")
+
+	// system
+	case "system model":
+		info.System.Model = value
+	case "system revision":
+		info.System.Revision = value
+	case "system bios version", "bios version":
+		info.System.BIOSVersion = value
+	case "service tag":
+		info.System.ServiceTag = value
+	case "express svc code", "express service code":
+		info.System.ExpressServiceCode = value
+	case "host name":
+		info.System.HostName = value
+	case "os name":
+		info.System.OSName = value
+	case "os version":
+		info.System.OSVersion = value
+	case "power status":
+		info.System.PowerStatus = value
+	}
+}
@@ -0,0 +1,155 @@
+package idrac
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"strings"
+)
+
+// FirmwareInventory is the parsed output of getversion. Versions holds every
+// component's version as reported, keyed by the component name.
+type FirmwareInventory struct {
+	BIOS                string            `json:"bios,omitempty"`
+	IDRAC               string            `json:"idrac,omitempty"`
+	LifecycleController string            `json:"lifecycle_controller,omitempty"`
+	Versions            map[string]string `json:"versions"`
+}
+
+// getversion executes the getversion subcommand to report firmware versions
+// using the specified flags.
+// See getversion in the iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) getversion(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	filter := ""
+	bios := false
+	cpld := false
+
+	fs := flag.NewFlagSet("getversion", flag.ExitOnError)
+	fs.StringVar(&filter, "f", "", "filter: bios, idrac, or lc (optional)")
+	fs.BoolVar(&bios, "b", false, "display the bios version")
+	fs.BoolVar(&cpld, "c", false, "display the cpld version")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags and build command
+	cmdInput := "racadm getversion"
+	switch filter {
+	case "":
+		// no-op
+	case "bios", "idrac", "lc":
+		cmdInput += " -f " + filter
+	default:
+		return execResponse{}, errors.New("filter (-f) must be bios, idrac, or lc")
+	}
+	if bios {
+		cmdInput += " -b"
+	}
+	if cpld {
+		cmdInput += " -c"
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// GetVersion returns the parsed firmware versions of the server's components
+func (rac *idrac) GetVersion(ctx context.Context) (FirmwareInventory, error) {
+	execResp, err := rac.getversion(ctx, nil)
+	if err != nil {
+		return FirmwareInventory{}, err
+	}
+
+	return ParseFirmwareInventory(execResp.Response.CommandOutput), nil
+}
+
+// ParseFirmwareInventory parses getversion output (Name = Version lines)
+func ParseFirmwareInventory(output string) FirmwareInventory {
+	inv := FirmwareInventory{
+		Versions: make(map[string]string),
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), "=")
+		if !found {
+			continue
+		}
+		key = strings.Join(strings.Fields(key), " ")
+		value = strings.TrimSpace(value)
+		if key == "" {
+			continue
+		}
+
+		inv.Versions[key] = value
+		switch strings.ToLower(key) {
+		case "bios version":
+			inv.BIOS = value
+		case "idrac version":
+			inv.IDRAC = value
+		case "lifecycle controller version", "usc version":
+			inv.LifecycleController = value
+		}
+	}
+
+	return inv
+}
+
+// loadFirmwareVersion reads the idrac's firmware version with getversion
+// (or getsysinfo, for idracs without getversion) and saves it on rac
+func (rac *idrac) loadFirmwareVersion(ctx context.Context) error {
+	raw := ""
+
+	execResp, err := rac.getversion(ctx, []string{"-f", "idrac"})
+	if err == nil {
+		raw = ParseFirmwareInventory(execResp.Response.CommandOutput).IDRAC
+	}
+	if raw == "" {
+		info, err := rac.GetSysInfo(ctx)
+		if err != nil {
+			return err
+		}
+		raw = info.RAC.FirmwareVersion
+	}
+
+	version, err := ParseFirmwareVersion(raw)
+	if err != nil {
+		return err
+	}
+
+	rac.firmwareVersion = version
+	return nil
+}
+
+// FirmwareVersion returns the idrac's firmware version. It is looked up
+// (see loadFirmwareVersion) the first time it is needed, unless Discover
+// already reported it, and cached after that.
+func (rac *idrac) FirmwareVersion(ctx context.Context) (FirmwareVersion, error) {
+	if rac.firmwareVersion.Raw != "" {
+		return rac.firmwareVersion, nil
+	}
+
+	err := rac.loadFirmwareVersion(ctx)
+	if err != nil {
+		return FirmwareVersion{}, err
+	}
+
+	return rac.firmwareVersion, nil
+}
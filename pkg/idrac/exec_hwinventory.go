@@ -0,0 +1,347 @@
+package idrac
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"strconv"
+	"strings"
+)
+
+// HardwareComponent is a single [InstanceID: ...] block of hwinventory
+// output. Properties holds every property as reported.
+type HardwareComponent struct {
+	FQDD       string            `json:"fqdd"`
+	DeviceType string            `json:"device_type"`
+	Status     string            `json:"status,omitempty"`
+	Properties map[string]string `json:"properties"`
+}
+
+// prop returns the value of the first of keys present in Properties
+func (comp HardwareComponent) prop(keys ...string) string {
+	for _, key := range keys {
+		if value, ok := comp.Properties[key]; ok {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// propInt is prop, converted to an int (0 if not a number)
+func (comp HardwareComponent) propInt(keys ...string) int {
+	i, _ := strconv.Atoi(strings.Fields(comp.prop(keys...) + " 0")[0])
+	return i
+}
+
+// CPU is a processor
+type CPU struct {
+	HardwareComponent
+	Model         string `json:"model"`
+	Manufacturer  string `json:"manufacturer"`
+	Cores         int    `json:"cores"`
+	Threads       int    `json:"threads"`
+	MaxClockSpeed string `json:"max_clock_speed"`
+}
+
+// DIMM is a memory module
+type DIMM struct {
+	HardwareComponent
+	Size         string `json:"size"`
+	Speed        string `json:"speed"`
+	MemoryType   string `json:"memory_type"`
+	Manufacturer string `json:"manufacturer"`
+	PartNumber   string `json:"part_number"`
+	SerialNumber string `json:"serial_number"`
+}
+
+// NIC is a network interface (port/partition)
+type NIC struct {
+	HardwareComponent
+	ProductName     string `json:"product_name"`
+	MACAddress      string `json:"mac_address"`
+	LinkSpeed       string `json:"link_speed"`
+	FirmwareVersion string `json:"firmware_version"`
+}
+
+// PSU is a power supply
+type PSU struct {
+	HardwareComponent
+	Model           string `json:"model"`
+	TotalOutput     string `json:"total_output_power"`
+	SerialNumber    string `json:"serial_number"`
+	PartNumber      string `json:"part_number"`
+	FirmwareVersion string `json:"firmware_version"`
+}
+
+// Disk is a physical disk
+type Disk struct {
+	HardwareComponent
+	Model                  string `json:"model"`
+	SerialNumber           string `json:"serial_number"`
+	Size                   string `json:"size"`
+	MediaType              string `json:"media_type"`
+	BusProtocol            string `json:"bus_protocol"`
+	RaidStatus             string `json:"raid_status"`
+	PredictiveFailureState string `json:"predictive_failure_state"`
+}
+
+// Controller is a storage controller
+type Controller struct {
+	HardwareComponent
+	ProductName     string `json:"product_name"`
+	FirmwareVersion string `json:"firmware_version"`
+	CacheSize       string `json:"cache_size"`
+}
+
+// HardwareInventory is the parsed output of hwinventory, by component type.
+// Components of other types are kept in Other.
+type HardwareInventory struct {
+	CPUs        []CPU               `json:"cpus"`
+	DIMMs       []DIMM              `json:"dimms"`
+	NICs        []NIC               `json:"nics"`
+	PSUs        []PSU               `json:"psus"`
+	Disks       []Disk              `json:"disks"`
+	Controllers []Controller        `json:"controllers"`
+	Other       []HardwareComponent `json:"other"`
+}
+
+// Components returns every component in the inventory
+func (inv HardwareInventory) Components() []HardwareComponent {
+	comps := []HardwareComponent{}
+	for _, c := range inv.CPUs {
+		comps = append(comps, c.HardwareComponent)
+	}
+	for _, c := range inv.DIMMs {
+		comps = append(comps, c.HardwareComponent)
+	}
+	for _, c := range inv.NICs {
+		comps = append(comps, c.HardwareComponent)
+	}
+	for _, c := range inv.PSUs {
+		comps = append(comps, c.HardwareComponent)
+	}
+	for _, c := range inv.Disks {
+		comps = append(comps, c.HardwareComponent)
+	}
+	for _, c := range inv.Controllers {
+		comps = append(comps, c.HardwareComponent)
+	}
+	comps = append(comps, inv.Other...)
+
+	return comps
+}
+
+// hwinventory executes the hwinventory subcommand using the specified flags.
+// Usage: hwinventory [fqdd]
+// hwinventory export -f file -u user -p password -l share
+// See hwinventory in the iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) hwinventory(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// export or fqdd are positional
+	operation := ""
+	if len(flags) > 0 && !strings.HasPrefix(flags[0], "-") {
+		operation = flags[0]
+		flags = flags[1:]
+	}
+
+	// parse command flags (options)
+	file := ""
+	share := NetworkShare{}
+
+	fs := flag.NewFlagSet("hwinventory", flag.ExitOnError)
+	fs.StringVar(&file, "f", "", "export file name on the share (export only)")
+	fs.StringVar(&share.Path, "l", "", "network share to export to, e.g. //server/share or server:/path (export only)")
+	fs.StringVar(&share.Username, "u", "", "network share username (export only)")
+	fs.StringVar(&share.Password, "p", "", "network share password (export only)")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags and build command; viewed inventory is far
+	// larger than the default output length
+	cmdInput := ""
+	maxOutputLen := maxOutputLenLarge
+	if operation == "export" {
+		maxOutputLen = "0x0fff"
+		if file == "" || share.Path == "" {
+			return execResponse{}, errors.New("file (-f) and share (-l) must be specified")
+		}
+		err = share.validate()
+		if err != nil {
+			return execResponse{}, err
+		}
+		if strings.ContainsAny(file, " \t\"/\\") {
+			return execResponse{}, errors.New("file name (-f) must not contain spaces, quotes, or slashes when exporting to a share")
+		}
+
+		cmdInput = "racadm hwinventory export -f " + file + share.flags()
+	} else {
+		if file != "" || share != (NetworkShare{}) {
+			return execResponse{}, errInvalidOrMalpositioned
+		}
+		if strings.ContainsAny(operation, " \t\"") {
+			return execResponse{}, errors.New("invalid fqdd")
+		}
+
+		cmdInput = "racadm hwinventory"
+		if operation != "" {
+			cmdInput += " " + operation
+		}
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = maxOutputLen
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// HardwareInventory returns the server's parsed hardware inventory
+func (rac *idrac) HardwareInventory(ctx context.Context) (HardwareInventory, error) {
+	execResp, err := rac.hwinventory(ctx, nil)
+	if err != nil {
+		return HardwareInventory{}, err
+	}
+
+	return ParseHardwareInventory(execResp.Response.CommandOutput), nil
+}
+
+// ExportHardwareInventory exports the inventory (as xml) to file on the
+// network share
+func (rac *idrac) ExportHardwareInventory(ctx context.Context, file string, share NetworkShare) error {
+	flags := []string{"export", "-f", file, "-l", share.Path}
+	if share.Username != "" {
+		flags = append(flags, "-u", share.Username)
+	}
+	if share.Password != "" {
+		flags = append(flags, "-p", share.Password)
+	}
+
+	_, err := rac.hwinventory(ctx, flags)
+	return err
+}
+
+// ParseHardwareInventory parses hwinventory output. Each component begins
+// with an [InstanceID: fqdd] header followed by Key = Value lines.
+func ParseHardwareInventory(output string) HardwareInventory {
+	comps := []HardwareComponent{}
+
+	var comp *HardwareComponent
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// new component header
+		if strings.HasPrefix(line, "[InstanceID:") && strings.HasSuffix(line, "]") {
+			comps = append(comps, HardwareComponent{
+				FQDD:       strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, "[InstanceID:"), "]")),
+				Properties: make(map[string]string),
+			})
+			comp = &comps[len(comps)-1]
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found || comp == nil {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		comp.Properties[key] = value
+		switch key {
+		case "Device Type":
+			comp.DeviceType = value
+		case "PrimaryStatus":
+			comp.Status = value
+		}
+	}
+
+	inv := HardwareInventory{}
+	for _, comp := range comps {
+		inv.add(comp)
+	}
+
+	return inv
+}
+
+// add adds comp to the appropriate typed list, based on the device type
+// (or, if that is missing, the fqdd prefix)
+func (inv *HardwareInventory) add(comp HardwareComponent) {
+	kind := strings.ToLower(comp.DeviceType)
+	if kind == "" {
+		kind = strings.ToLower(strings.SplitN(comp.FQDD, ".", 2)[0])
+	}
+
+	switch {
+	case kind == "cpu":
+		inv.CPUs = append(inv.CPUs, CPU{
+			HardwareComponent: comp,
+			Model:             comp.prop("Model"),
+			Manufacturer:      comp.prop("Manufacturer"),
+			Cores:             comp.propInt("NumberOfEnabledCores", "NumberOfProcessorCores"),
+			Threads:           comp.propInt("NumberOfEnabledThreads"),
+			MaxClockSpeed:     comp.prop("MaxClockSpeed"),
+		})
+	case kind == "memory" || kind == "dimm":
+		inv.DIMMs = append(inv.DIMMs, DIMM{
+			HardwareComponent: comp,
+			Size:              comp.prop("Size"),
+			Speed:             comp.prop("Speed", "CurrentOperatingSpeed"),
+			MemoryType:        comp.prop("MemoryType"),
+			Manufacturer:      comp.prop("Manufacturer"),
+			PartNumber:        comp.prop("PartNumber"),
+			SerialNumber:      comp.prop("SerialNumber"),
+		})
+	case kind == "nic":
+		inv.NICs = append(inv.NICs, NIC{
+			HardwareComponent: comp,
+			ProductName:       comp.prop("ProductName"),
+			MACAddress:        comp.prop("CurrentMACAddress", "PermanentMACAddress"),
+			LinkSpeed:         comp.prop("LinkSpeed"),
+			FirmwareVersion:   comp.prop("FamilyVersion", "ControllerBIOSVersion"),
+		})
+	case kind == "powersupply" || kind == "psu":
+		inv.PSUs = append(inv.PSUs, PSU{
+			HardwareComponent: comp,
+			Model:             comp.prop("Model"),
+			TotalOutput:       comp.prop("TotalOutputPower"),
+			SerialNumber:      comp.prop("SerialNumber"),
+			PartNumber:        comp.prop("PartNumber"),
+			FirmwareVersion:   comp.prop("FirmwareVersion"),
+		})
+	case kind == "physicaldisk" || kind == "disk" || kind == "pcie ssd":
+		inv.Disks = append(inv.Disks, Disk{
+			HardwareComponent:      comp,
+			Model:                  comp.prop("Model"),
+			SerialNumber:           comp.prop("SerialNumber"),
+			Size:                   comp.prop("SizeInBytes", "Size"),
+			MediaType:              comp.prop("MediaType"),
+			BusProtocol:            comp.prop("BusProtocol"),
+			RaidStatus:             comp.prop("RaidStatus"),
+			PredictiveFailureState: comp.prop("PredictiveFailureState"),
+		})
+	case strings.Contains(kind, "controller") || kind == "raid":
+		inv.Controllers = append(inv.Controllers, Controller{
+			HardwareComponent: comp,
+			ProductName:       comp.prop("ProductName"),
+			FirmwareVersion:   comp.prop("ControllerFirmwareVersion"),
+			CacheSize:         comp.prop("CacheSizeInMB", "CacheSize"),
+		})
+	default:
+		inv.Other = append(inv.Other, comp)
+	}
+}
@@ -0,0 +1,384 @@
+package idrac
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JobRebootType is the reboot type used when creating a job
+type JobRebootType string
+
+// valid reboot types (jobqueue create -r)
+const (
+	JobRebootNone     = JobRebootType("none")
+	JobRebootPwrCycle = JobRebootType("pwrcycle")
+	JobRebootGraceful = JobRebootType("graceful")
+	JobRebootForced   = JobRebootType("forced")
+)
+
+const (
+	jobStartNow      = "TIME_NOW"
+	jobClearAll      = "JID_CLEARALL"
+	jobClearAllForce = "JID_CLEARALL_FORCE"
+	jobTimeLayout    = "20060102150405"
+	jobPollInterval  = 10 * time.Second
+)
+
+var (
+	errJobIDRequired = errors.New("job id (-i) must be specified")
+	jobIDRegex       = regexp.MustCompile(`\b(?:JID|RID)_[0-9]+\b`)
+)
+
+// Job is a Lifecycle Controller job as reported by jobqueue view
+type Job struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Status          string `json:"status"`
+	StartTime       string `json:"start_time,omitempty"`
+	ExpirationTime  string `json:"expiration_time,omitempty"`
+	Message         string `json:"message,omitempty"`
+	PercentComplete int    `json:"percent_complete"`
+}
+
+// Done returns true if the job has finished (successfully or not), e.g.
+// Completed, Completed with Errors, Reboot Completed (reboot jobs), Failed,
+// Reboot Failed or Cancelled
+func (job Job) Done() bool {
+	status := strings.ToLower(job.Status)
+	return strings.Contains(status, "completed") ||
+		strings.Contains(status, "failed") ||
+		strings.Contains(status, "cancel")
+}
+
+// Succeeded returns true if the job completed without errors (Completed, or
+// Reboot Completed for a reboot job)
+func (job Job) Succeeded() bool {
+	return strings.EqualFold(job.Status, "Completed") || strings.EqualFold(job.Status, "Reboot Completed")
+}
+
+// jobqueue executes the jobqueue subcommand to view, create or delete
+// Lifecycle Controller jobs.
+// Usage: jobqueue view [-i jobid]
+// jobqueue create <fqdd> [-r none|pwrcycle|graceful|forced] [-s start] [-e expiry] [--realtime]
+// jobqueue delete -i <jobid|JID_CLEARALL|JID_CLEARALL_FORCE>
+// See jobqueue in the iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) jobqueue(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// operation (and for create, the fqdd) is positional
+	if len(flags) == 0 {
+		return execResponse{}, errors.New("jobqueue operation (view, create, or delete) must be specified")
+	}
+	operation := flags[0]
+	flags = flags[1:]
+
+	fqdd := ""
+	if operation == "create" {
+		if len(flags) == 0 || strings.HasPrefix(flags[0], "-") {
+			return execResponse{}, errors.New("jobqueue create requires an fqdd")
+		}
+		fqdd = flags[0]
+		flags = flags[1:]
+	}
+
+	// parse command flags (options)
+	jobID := ""
+	rebootType := ""
+	startTime := ""
+	expiryTime := ""
+	realtime := false
+
+	fs := flag.NewFlagSet("jobqueue", flag.ExitOnError)
+	fs.StringVar(&jobID, "i", "", "job id (view: optional, delete: required)")
+	fs.StringVar(&rebootType, "r", "", "reboot type: none, pwrcycle, graceful, or forced (create only)")
+	fs.StringVar(&startTime, "s", "", "start time: TIME_NOW or yyyymmddhhmmss (create only)")
+	fs.StringVar(&expiryTime, "e", "", "expiration time: yyyymmddhhmmss (create only)")
+	fs.BoolVar(&realtime, "realtime", false, "create a real time job (create only)")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags and build command
+	if strings.ContainsAny(jobID+fqdd, " \t\"") {
+		return execResponse{}, errors.New("invalid job id or fqdd")
+	}
+
+	cmdInput := ""
+	maxOutputLen := "0x0fff"
+	switch operation {
+	case "view":
+		if rebootType != "" || startTime != "" || expiryTime != "" || realtime {
+			return execResponse{}, errInvalidOrMalpositioned
+		}
+		cmdInput = "racadm jobqueue view"
+		if jobID != "" {
+			cmdInput += " -i " + jobID
+		}
+		// the whole queue can be long
+		maxOutputLen = maxOutputLenLarge
+
+	case "create":
+		if jobID != "" {
+			return execResponse{}, errInvalidOrMalpositioned
+		}
+		cmdInput = "racadm jobqueue create " + fqdd
+
+		if rebootType != "" {
+			switch JobRebootType(rebootType) {
+			case JobRebootNone, JobRebootPwrCycle, JobRebootGraceful, JobRebootForced:
+				cmdInput += " -r " + rebootType
+			default:
+				return execResponse{}, errors.New("reboot type (-r) must be none, pwrcycle, graceful, or forced")
+			}
+		}
+		for _, t := range []struct {
+			value string
+			param string
+		}{
+			{startTime, "-s"},
+			{expiryTime, "-e"},
+		} {
+			if t.value == "" {
+				continue
+			}
+			if t.value != jobStartNow {
+				_, err = time.Parse(jobTimeLayout, t.value)
+				if err != nil {
+					return execResponse{}, fmt.Errorf("invalid time (%s), must be TIME_NOW or yyyymmddhhmmss", t.value)
+				}
+			}
+			cmdInput += fmt.Sprintf(" %s %s", t.param, t.value)
+		}
+		if realtime {
+			cmdInput += " --realtime"
+		}
+
+	case "delete":
+		if rebootType != "" || startTime != "" || expiryTime != "" || realtime {
+			return execResponse{}, errInvalidOrMalpositioned
+		}
+		if jobID == "" {
+			return execResponse{}, errJobIDRequired
+		}
+		cmdInput = "racadm jobqueue delete -i " + jobID
+
+	default:
+		return execResponse{}, fmt.Errorf("invalid jobqueue operation (%s)", operation)
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = maxOutputLen
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// ParseJobs parses jobqueue view output into jobs. Each job starts with a
+// [Job ID=...] header followed by Key=Value lines; values may be bracketed.
+func ParseJobs(output string) []Job {
+	jobs := []Job{}
+
+	var job *Job
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// new job header
+		if strings.HasPrefix(line, "[Job ID=") && strings.HasSuffix(line, "]") {
+			jobs = append(jobs, Job{
+				ID: strings.TrimSuffix(strings.TrimPrefix(line, "[Job ID="), "]"),
+			})
+			job = &jobs[len(jobs)-1]
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found || job == nil {
+			continue
+		}
+		value = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(value), "["), "]")
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "job name":
+			job.Name = value
+		case "status":
+			job.Status = value
+		case "start time":
+			job.StartTime = value
+		case "expiration time":
+			job.ExpirationTime = value
+		case "message":
+			job.Message = value
+		case "percent complete":
+			job.PercentComplete, _ = strconv.Atoi(value)
+		}
+	}
+
+	return jobs
+}
+
+// JobQueue returns all jobs in the job queue
+func (rac *idrac) JobQueue(ctx context.Context) ([]Job, error) {
+	execResp, err := rac.jobqueue(ctx, []string{"view"})
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseJobs(execResp.Response.CommandOutput), nil
+}
+
+// GetJob returns the job with the specified id
+func (rac *idrac) GetJob(ctx context.Context, jobID string) (Job, error) {
+	if jobID == "" {
+		return Job{}, errJobIDRequired
+	}
+
+	execResp, err := rac.jobqueue(ctx, []string{"view", "-i", jobID})
+	if err != nil {
+		return Job{}, err
+	}
+
+	jobs := ParseJobs(execResp.Response.CommandOutput)
+	for _, job := range jobs {
+		if job.ID == jobID {
+			return job, nil
+		}
+	}
+
+	return Job{}, fmt.Errorf("job %s not found", jobID)
+}
+
+// JobCreateOptions are the options to create a job with. A zero StartTime
+// starts the job now and a zero ExpirationTime never expires.
+type JobCreateOptions struct {
+	RebootType     JobRebootType
+	StartTime      time.Time
+	ExpirationTime time.Time
+	Realtime       bool
+}
+
+// CreateJob creates a configuration job to apply pending changes to fqdd
+// (e.g. BIOS.Setup.1-1). It returns the ids of the created jobs: the
+// configuration job and, if a reboot was requested, the reboot job.
+func (rac *idrac) CreateJob(ctx context.Context, fqdd string, opts JobCreateOptions) ([]string, error) {
+	flags := []string{"create", fqdd}
+	if opts.RebootType != "" {
+		flags = append(flags, "-r", string(opts.RebootType))
+	}
+	if opts.StartTime.IsZero() {
+		flags = append(flags, "-s", jobStartNow)
+	} else {
+		flags = append(flags, "-s", opts.StartTime.Format(jobTimeLayout))
+	}
+	if !opts.ExpirationTime.IsZero() {
+		flags = append(flags, "-e", opts.ExpirationTime.Format(jobTimeLayout))
+	}
+	if opts.Realtime {
+		flags = append(flags, "--realtime")
+	}
+
+	execResp, err := rac.jobqueue(ctx, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := parseJobIDs(execResp.Response.CommandOutput)
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("job created but no job id in output (%s)", strings.TrimSpace(execResp.Response.CommandOutput))
+	}
+
+	return ids, nil
+}
+
+// parseJobIDs returns the unique job (JID_) and reboot (RID_) ids in output,
+// in order
+func parseJobIDs(output string) []string {
+	ids := []string{}
+	seen := make(map[string]struct{})
+	for _, id := range jobIDRegex.FindAllString(output, -1) {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// DeleteJob deletes the job with the specified id
+func (rac *idrac) DeleteJob(ctx context.Context, jobID string) error {
+	if jobID == "" {
+		return errJobIDRequired
+	}
+
+	_, err := rac.jobqueue(ctx, []string{"delete", "-i", jobID})
+	return err
+}
+
+// ClearJobQueue deletes all jobs. If force is true JID_CLEARALL_FORCE is
+// used, which also clears jobs that are running and resets the Lifecycle
+// Controller's pending configuration.
+func (rac *idrac) ClearJobQueue(ctx context.Context, force bool) error {
+	jobID := jobClearAll
+	if force {
+		jobID = jobClearAllForce
+	}
+
+	return rac.DeleteJob(ctx, jobID)
+}
+
+// WaitForJob polls the job until it is done or ctx is done. Progress is
+// logged whenever the percent complete changes. An error is returned if the
+// job doesn't complete successfully.
+func (rac *idrac) WaitForJob(ctx context.Context, jobID string) (Job, error) {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	lastPercent := -1
+	var lastErr error
+	for {
+		job, err := rac.GetJob(ctx, jobID)
+		lastErr = err
+		if err == nil {
+			if job.PercentComplete != lastPercent {
+				log.Printf("job %s: %s (%d%%)", job.ID, job.Status, job.PercentComplete)
+				lastPercent = job.PercentComplete
+			}
+
+			if job.Done() {
+				if !job.Succeeded() {
+					return job, fmt.Errorf("job %s %s (%s)", job.ID, strings.ToLower(job.Status), job.Message)
+				}
+				return job, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return job, fmt.Errorf("job %s did not complete (%w) (last error: %s)", jobID, ctx.Err(), lastErr)
+			}
+			return job, fmt.Errorf("job %s did not complete (%w)", jobID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lclogTimeLayout is the timestamp format lclog view accepts for -r and -t
+const lclogTimeLayout = "2006-01-02 15:04:05"
+
+// lclog executes the lclog subcommand to read the Lifecycle log using the
+// specified flags. Only the view operation is currently supported.
+// Usage: lclog view [-i msgid] [-a agent] [-c category] [-s severity]
+// [-b seq] [-e seq] [-r start] [-t end] [-q record] [-n count]
+// See lclog in the iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) lclog(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// operation is positional and comes before any flags
+	if len(flags) == 0 || flags[0] != "view" {
+		return execResponse{}, errors.New("lclog operation must be view (others not implemented)")
+	}
+	flags = flags[1:]
+
+	// parse command flags (options)
+	messageID := ""
+	agent := ""
+	category := ""
+	severity := ""
+	seqStart := 0
+	seqEnd := 0
+	startTime := ""
+	endTime := ""
+	recordStart := 0
+	count := 0
+
+	fs := flag.NewFlagSet("lclog", flag.ExitOnError)
+	fs.StringVar(&messageID, "i", "", "message id (optional)")
+	fs.StringVar(&agent, "a", "", "agent (e.g. iDRAC, UEFI, RACLOG) (optional)")
+	fs.StringVar(&category, "c", "", "category (e.g. System, Storage, Updates, Audit, Configuration, Worknotes) (optional)")
+	fs.StringVar(&severity, "s", "", "severity (Informational, Warning, or Critical) (optional)")
+	fs.IntVar(&seqStart, "b", 0, "first sequence number to display (optional)")
+	fs.IntVar(&seqEnd, "e", 0, "last sequence number to display (optional)")
+	fs.StringVar(&startTime, "r", "", "start timestamp, yyyy-mm-dd HH:MM:SS (optional)")
+	fs.StringVar(&endTime, "t", "", "end timestamp, yyyy-mm-dd HH:MM:SS (optional)")
+	fs.IntVar(&recordStart, "q", 0, "record to start displaying from (optional)")
+	fs.IntVar(&count, "n", 0, "maximum number of records to display (optional)")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags and build command
+	if strings.ContainsAny(messageID+agent+category+severity, " \t\"") {
+		return execResponse{}, errors.New("invalid message id, agent, category, or severity")
+	}
+	if seqStart < 0 || seqEnd < 0 || recordStart < 0 || count < 0 {
+		return execResponse{}, errors.New("-b, -e, -q, and -n must be positive")
+	}
+	for _, ts := range []string{startTime, endTime} {
+		if ts == "" {
+			continue
+		}
+		_, err = time.Parse(lclogTimeLayout, ts)
+		if err != nil {
+			return execResponse{}, fmt.Errorf("invalid timestamp (%s), must be yyyy-mm-dd HH:MM:SS", ts)
+		}
+	}
+
+	cmdInput := "racadm lclog view"
+	for _, f := range []struct {
+		value string
+		param string
+	}{
+		{messageID, "-i"},
+		{agent, "-a"},
+		{category, "-c"},
+		{severity, "-s"},
+	} {
+		if f.value != "" {
+			cmdInput += fmt.Sprintf(" %s %s", f.param, f.value)
+		}
+	}
+	for _, f := range []struct {
+		value int
+		param string
+	}{
+		{seqStart, "-b"},
+		{seqEnd, "-e"},
+		{recordStart, "-q"},
+		{count, "-n"},
+	} {
+		if f.value != 0 {
+			cmdInput += fmt.Sprintf(" %s %d", f.param, f.value)
+		}
+	}
+	if startTime != "" {
+		cmdInput += fmt.Sprintf(" -r \"%s\"", startTime)
+	}
+	if endTime != "" {
+		cmdInput += fmt.Sprintf(" -t \"%s\"", endTime)
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = maxOutputLenLarge
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// LCLogFilter selects Lifecycle log records. Zero values don't filter.
+type LCLogFilter struct {
+	Category      string
+	Severity      string
+	Start         time.Time
+	End           time.Time
+	FirstSequence int
+	Count         int
+}
+
+// flags returns the lclog view flags for filter
+func (filter LCLogFilter) flags() []string {
+	flags := []string{"view"}
+	if filter.Category != "" {
+		flags = append(flags, "-c", filter.Category)
+	}
+	if filter.Severity != "" {
+		flags = append(flags, "-s", filter.Severity)
+	}
+	if !filter.Start.IsZero() {
+		flags = append(flags, "-r", filter.Start.Format(lclogTimeLayout))
+	}
+	if !filter.End.IsZero() {
+		flags = append(flags, "-t", filter.End.Format(lclogTimeLayout))
+	}
+	if filter.FirstSequence != 0 {
+		flags = append(flags, "-b", strconv.Itoa(filter.FirstSequence))
+	}
+	if filter.Count != 0 {
+		flags = append(flags, "-n", strconv.Itoa(filter.Count))
+	}
+
+	return flags
+}
+
+// GetLCLog returns the Lifecycle log records that match filter
+func (rac *idrac) GetLCLog(ctx context.Context, filter LCLogFilter) ([]LogRecord, error) {
+	execResp, err := rac.lclog(ctx, filter.flags())
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseLogRecords(execResp.Response.CommandOutput), nil
+}
+
+// LCLogSince returns the Lifecycle log records (that match filter) newer
+// than cursor and the cursor advanced past them. A zero cursor returns the
+// whole log.
+func (rac *idrac) LCLogSince(ctx context.Context, filter LCLogFilter, cursor LogCursor) ([]LogRecord, LogCursor, error) {
+	if cursor.Sequence != 0 {
+		filter.FirstSequence = cursor.Sequence + 1
+	}
+
+	records, err := rac.GetLCLog(ctx, filter)
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	records = cursor.newRecords(records)
+	return records, cursor.Advance(records), nil
+}
@@ -0,0 +1,400 @@
+package idrac
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// licenseRemoteFile is the name a license is put on (or exported to) the
+// idrac as
+const licenseRemoteFile = "license.xml"
+
+// licenseMaxOutputLen is the output length requested for a license export
+const licenseMaxOutputLen = "0xffff"
+
+// licenseExpirationLayouts are the formats license view reports expiration
+// times in
+var licenseExpirationLayouts = []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+var errLicenseNoContent = errors.New("the idrac did not return the license (export to a network share with -l instead)")
+
+// LicenseEntitlement is an installed license, as reported by license view
+type LicenseEntitlement struct {
+	Device            string `json:"device"`
+	DeviceDescription string `json:"device_description,omitempty"`
+	UniqueIdentifier  string `json:"unique_identifier,omitempty"`
+	Number            int    `json:"number"`
+	Status            string `json:"status"`
+	TransactionID     string `json:"transaction_id,omitempty"`
+	Description       string `json:"description"`
+	Type              string `json:"type"`
+	EntitlementID     string `json:"entitlement_id"`
+	Bound             string `json:"bound,omitempty"`
+	Expiration        string `json:"expiration"`
+	// ExpiresAt is nil if the license doesn't expire or Expiration couldn't
+	// be parsed (see ExpirationError)
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// ExpirationError is set if Expiration is a date in an unknown format
+	ExpirationError string `json:"expiration_error,omitempty"`
+}
+
+// Perpetual returns true if the license never expires, i.e. its type is
+// PERPETUAL or its expiration is N/A. A license whose expiration couldn't
+// be parsed isn't perpetual.
+func (ent LicenseEntitlement) Perpetual() bool {
+	return strings.EqualFold(ent.Type, "PERPETUAL") || strings.EqualFold(ent.Expiration, "N/A")
+}
+
+// Expired returns true if the license expired before now
+func (ent LicenseEntitlement) Expired(now time.Time) bool {
+	return ent.ExpiresAt != nil && ent.ExpiresAt.Before(now)
+}
+
+// license executes the license subcommand to view, import or export
+// licenses. Import and export use a local file, or a network share with -l.
+// Usage: license view [-c fqdd]
+// license import -f <file> -c <fqdd> [-l share [-u user] [-p password]]
+// license export -f <file> -e <entitlement id> | -c <fqdd> [-l share [-u user] [-p password]]
+// See license in the iDRAC7, iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) license(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// operation is positional
+	if len(flags) == 0 {
+		return execResponse{}, errors.New("license operation (view, import, or export) must be specified")
+	}
+	operation := flags[0]
+	flags = flags[1:]
+
+	// parse command flags (options)
+	fqdd := ""
+	file := ""
+	entitlementID := ""
+	share := NetworkShare{}
+
+	fs := flag.NewFlagSet("license "+operation, flag.ExitOnError)
+	fs.StringVar(&fqdd, "c", "", "device fqdd, e.g. iDRAC.Embedded.1")
+	switch operation {
+	case "view":
+		// no other flags
+	case "import", "export":
+		fs.StringVar(&file, "f", "", "license file (required)")
+		fs.StringVar(&share.Path, "l", "", "network share, e.g. //server/share or server:/path (optional)")
+		fs.StringVar(&share.Username, "u", "", "network share username (optional)")
+		fs.StringVar(&share.Password, "p", "", "network share password (optional)")
+		if operation == "export" {
+			fs.StringVar(&entitlementID, "e", "", "entitlement id of the license to export")
+		}
+	default:
+		return execResponse{}, fmt.Errorf("invalid license operation %s (must be view, import, or export)", operation)
+	}
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags and build command
+	if strings.ContainsAny(fqdd+entitlementID, " \t\"") {
+		return execResponse{}, errors.New("fqdd and entitlement id must not contain spaces or quotes")
+	}
+	cmdInput := "racadm license " + operation
+	switch operation {
+	case "view":
+		if fqdd != "" {
+			cmdInput += " -c " + fqdd
+		}
+
+	case "import", "export":
+		if file == "" {
+			return execResponse{}, errors.New("license file (-f) must be specified")
+		}
+		err = share.validate()
+		if err != nil {
+			return execResponse{}, err
+		}
+
+		remoteFile := licenseRemoteFile
+		if share.Path != "" {
+			if strings.ContainsAny(file, " \t\"/\\") {
+				return execResponse{}, errors.New("file name (-f) must not contain spaces, quotes, or slashes with a network share")
+			}
+			remoteFile = file
+		}
+
+		if operation == "import" {
+			if fqdd == "" {
+				return execResponse{}, errors.New("device fqdd (-c) must be specified")
+			}
+			if share.Path == "" {
+				content, err := os.ReadFile(file)
+				if err != nil {
+					return execResponse{}, err
+				}
+				err = rac.putLicense(ctx, content)
+				if err != nil {
+					return execResponse{}, err
+				}
+			}
+			cmdInput += " -f " + remoteFile + " -c " + fqdd
+		} else {
+			if (entitlementID == "") == (fqdd == "") {
+				return execResponse{}, errors.New("exactly one of entitlement id (-e) or device fqdd (-c) must be specified")
+			}
+			cmdInput += " -f " + remoteFile
+			if entitlementID != "" {
+				cmdInput += " -e " + entitlementID
+			} else {
+				cmdInput += " -c " + fqdd
+			}
+		}
+		cmdInput += share.flags()
+	}
+
+	// a local export returns the license in the output
+	maxOutputLen := "0x0fff"
+	if operation == "export" && share.Path == "" {
+		maxOutputLen = licenseMaxOutputLen
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = maxOutputLen
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload; a local export returns the license, which isn't
+	// logged
+	if operation == "export" {
+		ctx = withQuietOutput(ctx)
+	}
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// local export: the license is returned in the output, save it
+	if operation == "export" && share.Path == "" {
+		license, err := extractLicense(execResp.Response.CommandOutput)
+		if err != nil {
+			return execResponse{}, err
+		}
+		err = os.WriteFile(file, license, 0600)
+		if err != nil {
+			return execResponse{}, err
+		}
+	}
+
+	return execResp, nil
+}
+
+// checkLicenseXML confirms data is a well formed xml document
+func checkLicenseXML(data []byte) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		return errors.New("license file is not xml")
+	}
+
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("license file is not valid xml (%w)", err)
+		}
+	}
+}
+
+// putLicense checks the license is xml and puts it on the idrac. The license
+// is signed, so it is put as is (binary), without new line normalization.
+func (rac *idrac) putLicense(ctx context.Context, content []byte) error {
+	err := checkLicenseXML(content)
+	if err != nil {
+		return err
+	}
+
+	return rac.putfileContext(ctx, putfilePayload{
+		filename: licenseRemoteFile,
+		flags:    0,
+		content:  content,
+		binary:   true,
+	})
+}
+
+// extractLicense returns the license xml in export output (racadm messages
+// may come before it)
+func extractLicense(output string) ([]byte, error) {
+	start := strings.Index(output, "<")
+	end := strings.LastIndex(output, ">")
+	if start < 0 || end < start {
+		return nil, errLicenseNoContent
+	}
+
+	license := []byte(output[start : end+1])
+	err := checkLicenseXML(license)
+	if err != nil {
+		return nil, fmt.Errorf("incomplete license (%w)", err)
+	}
+
+	return license, nil
+}
+
+// ParseLicenses parses license view output. Each device starts with an
+// unindented FQDD line, followed by its Key = Value properties and then a
+// "License #n" block of properties for each license.
+func ParseLicenses(output string) []LicenseEntitlement {
+	licenses := []LicenseEntitlement{}
+
+	device := LicenseEntitlement{}
+	var license *LicenseEntitlement
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		rawLine := scanner.Text()
+		line := strings.TrimSpace(rawLine)
+		if line == "" {
+			continue
+		}
+
+		// new license on the device (checked first, since some idracs
+		// don't indent it)
+		if strings.HasPrefix(line, "License #") {
+			ent := LicenseEntitlement{
+				Device:            device.Device,
+				DeviceDescription: device.DeviceDescription,
+				UniqueIdentifier:  device.UniqueIdentifier,
+			}
+			ent.Number, _ = strconv.Atoi(strings.TrimPrefix(line, "License #"))
+			licenses = append(licenses, ent)
+			license = &licenses[len(licenses)-1]
+			continue
+		}
+
+		// new device
+		if rawLine == line && !strings.Contains(line, "=") {
+			device = LicenseEntitlement{Device: line}
+			license = nil
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		// device properties
+		if license == nil {
+			switch key {
+			case "device":
+				device.Device = value
+			case "device description":
+				device.DeviceDescription = value
+			case "unique identifier":
+				device.UniqueIdentifier = value
+			}
+			continue
+		}
+
+		// license properties
+		switch key {
+		case "status":
+			license.Status = value
+		case "transaction id":
+			license.TransactionID = value
+		case "license description":
+			license.Description = value
+		case "license type":
+			license.Type = value
+		case "entitlement id":
+			license.EntitlementID = value
+		case "license bound":
+			license.Bound = value
+		case "expiration":
+			license.Expiration = value
+			license.ExpiresAt, license.ExpirationError = parseLicenseExpiration(value)
+		}
+	}
+
+	return licenses
+}
+
+// parseLicenseExpiration parses a license view expiration. N/A (or empty)
+// is no expiration; anything else that doesn't parse returns the reason.
+func parseLicenseExpiration(value string) (*time.Time, string) {
+	if value == "" || strings.EqualFold(value, "N/A") {
+		return nil, ""
+	}
+
+	for _, layout := range licenseExpirationLayouts {
+		expiresAt, err := time.Parse(layout, value)
+		if err == nil {
+			return &expiresAt, ""
+		}
+	}
+
+	return nil, fmt.Sprintf("unknown expiration format %q", value)
+}
+
+// Licenses returns the installed licenses
+func (rac *idrac) Licenses(ctx context.Context) ([]LicenseEntitlement, error) {
+	execResp, err := rac.license(ctx, []string{"view"})
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseLicenses(execResp.Response.CommandOutput), nil
+}
+
+// ImportLicense installs the license (xml) on the device fqdd (e.g.
+// iDRAC.Embedded.1)
+func (rac *idrac) ImportLicense(ctx context.Context, license []byte, fqdd string) error {
+	if fqdd == "" || strings.ContainsAny(fqdd, " \t\"") {
+		return errors.New("invalid device fqdd")
+	}
+
+	err := rac.putLicense(ctx, license)
+	if err != nil {
+		return err
+	}
+
+	payload := execPayload{}
+	payload.Request.CommandInput = fmt.Sprintf("racadm license import -f %s -c %s", licenseRemoteFile, fqdd)
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	_, err = rac.executePayloadContext(ctx, payload)
+	return err
+}
+
+// ExportLicense returns the license (xml) with the entitlement id
+func (rac *idrac) ExportLicense(ctx context.Context, entitlementID string) ([]byte, error) {
+	if entitlementID == "" || strings.ContainsAny(entitlementID, " \t\"") {
+		return nil, errors.New("invalid entitlement id")
+	}
+
+	payload := execPayload{}
+	payload.Request.CommandInput = fmt.Sprintf("racadm license export -f %s -e %s", licenseRemoteFile, entitlementID)
+	payload.Request.MaxOutputLen = licenseMaxOutputLen
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	execResp, err := rac.executePayloadContext(withQuietOutput(ctx), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return extractLicense(execResp.Response.CommandOutput)
+}
@@ -0,0 +1,215 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// NICConfig is the parsed output of getniccfg
+type NICConfig struct {
+	NICEnabled   bool                         `json:"nic_enabled"`
+	IPv4Enabled  bool                         `json:"ipv4_enabled"`
+	DHCPEnabled  bool                         `json:"dhcp_enabled"`
+	IPAddress    string                       `json:"ip_address"`
+	SubnetMask   string                       `json:"subnet_mask"`
+	Gateway      string                       `json:"gateway"`
+	VLANEnabled  bool                         `json:"vlan_enabled"`
+	VLANID       int                          `json:"vlan_id,omitempty"`
+	VLANPriority int                          `json:"vlan_priority,omitempty"`
+	NICSelection string                       `json:"nic_selection,omitempty"`
+	LinkDetected string                       `json:"link_detected,omitempty"`
+	Speed        string                       `json:"speed,omitempty"`
+	Duplex       string                       `json:"duplex,omitempty"`
+	Sections     map[string]map[string]string `json:"sections"`
+}
+
+// getniccfg executes the getniccfg subcommand to display the idrac's network
+// settings.
+// See getniccfg in the iDRAC7, iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) getniccfg(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// no flags are supported (-m is chassis only)
+	if len(flags) > 0 {
+		return execResponse{}, errInvalidOrMalpositioned
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = "racadm getniccfg"
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// GetNICConfig returns the idrac's parsed network settings
+func (rac *idrac) GetNICConfig(ctx context.Context) (NICConfig, error) {
+	execResp, err := rac.getniccfg(ctx, nil)
+	if err != nil {
+		return NICConfig{}, err
+	}
+
+	return ParseNICConfig(execResp.Response.CommandOutput), nil
+}
+
+// ParseNICConfig parses getniccfg output. It is sectioned the same way as
+// getsysinfo, so the same parser is used for the sections.
+func ParseNICConfig(output string) NICConfig {
+	sections := ParseSystemInfo(output).Sections
+	cfg := NICConfig{Sections: sections}
+
+	isTrue := func(value string) bool {
+		value = strings.ToLower(value)
+		return value == "1" || value == "enabled" || value == "yes"
+	}
+
+	for section, values := range sections {
+		// static and ipv6 settings aren't the current ipv4 settings
+		lowerSection := strings.ToLower(section)
+		if strings.Contains(lowerSection, "static") || strings.Contains(lowerSection, "ipv6") {
+			continue
+		}
+
+		for key, value := range values {
+			switch strings.ToLower(key) {
+			case "nic enabled":
+				cfg.NICEnabled = isTrue(value)
+			case "ipv4 enabled":
+				cfg.IPv4Enabled = isTrue(value)
+			case "dhcp enabled":
+				cfg.DHCPEnabled = isTrue(value)
+			case "ip address":
+				cfg.IPAddress = value
+			case "subnet mask":
+				cfg.SubnetMask = value
+			case "gateway":
+				cfg.Gateway = value
+			case "vlan enable", "vlan enabled":
+				cfg.VLANEnabled = isTrue(value)
+			case "vlan id":
+				cfg.VLANID, _ = strconv.Atoi(value)
+			case "vlan priority":
+				cfg.VLANPriority, _ = strconv.Atoi(value)
+			case "nic selection":
+				cfg.NICSelection = value
+			case "link detected":
+				cfg.LinkDetected = value
+			case "speed":
+				cfg.Speed = value
+			case "duplex mode":
+				cfg.Duplex = value
+			}
+		}
+	}
+
+	return cfg
+}
+
+// setniccfg executes the setniccfg subcommand to change the idrac's network
+// settings. Exactly one option may be specified.
+// Usage: setniccfg -d | -s <ip> <netmask> <gateway> | -o | -v [<vlan id> <vlan priority>]
+// See setniccfg in the iDRAC7, iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) setniccfg(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// options take a variable number of args, so flag isn't used
+	if len(flags) == 0 {
+		return execResponse{}, errors.New("an option (-d, -s, -o, or -v) must be specified")
+	}
+
+	cmdInput := ""
+	switch flags[0] {
+	case "-d", "-o":
+		if len(flags) != 1 {
+			return execResponse{}, errInvalidOrMalpositioned
+		}
+		cmdInput = "racadm setniccfg " + flags[0]
+
+	case "-s":
+		if len(flags) != 4 {
+			return execResponse{}, errors.New("-s requires ip address, netmask, and gateway")
+		}
+		for _, addr := range flags[1:] {
+			ip := net.ParseIP(addr)
+			if ip == nil || ip.To4() == nil {
+				return execResponse{}, fmt.Errorf("invalid ipv4 address (%s)", addr)
+			}
+		}
+		cmdInput = fmt.Sprintf("racadm setniccfg -s %s %s %s", flags[1], flags[2], flags[3])
+
+	case "-v":
+		switch len(flags) {
+		case 1:
+			// disable vlan
+			cmdInput = "racadm setniccfg -v"
+		case 3:
+			vlanID, err := strconv.Atoi(flags[1])
+			if err != nil || vlanID < 1 || vlanID > 4094 {
+				return execResponse{}, errors.New("vlan id must be between 1 and 4094")
+			}
+			priority, err := strconv.Atoi(flags[2])
+			if err != nil || priority < 0 || priority > 7 {
+				return execResponse{}, errors.New("vlan priority must be between 0 and 7")
+			}
+			cmdInput = fmt.Sprintf("racadm setniccfg -v %d %d", vlanID, priority)
+		default:
+			return execResponse{}, errors.New("-v requires both vlan id and priority (or neither, to disable)")
+		}
+
+	default:
+		return execResponse{}, errInvalidOrMalpositioned
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// SetNICCfg runs setniccfg with racadm style options (e.g. -s ip mask gw)
+func (rac *idrac) SetNICCfg(ctx context.Context, flags []string) error {
+	_, err := rac.setniccfg(ctx, flags)
+	return err
+}
+
+// SetNICStatic sets a static ipv4 address
+func (rac *idrac) SetNICStatic(ctx context.Context, ipAddress, netmask, gateway string) error {
+	_, err := rac.setniccfg(ctx, []string{"-s", ipAddress, netmask, gateway})
+	return err
+}
+
+// SetNICDHCP enables DHCP
+func (rac *idrac) SetNICDHCP(ctx context.Context) error {
+	_, err := rac.setniccfg(ctx, []string{"-d"})
+	return err
+}
+
+// SetNICVLAN enables the vlan with the specified id and priority
+func (rac *idrac) SetNICVLAN(ctx context.Context, vlanID, priority int) error {
+	_, err := rac.setniccfg(ctx, []string{"-v", strconv.Itoa(vlanID), strconv.Itoa(priority)})
+	return err
+}
+
+// DisableNICVLAN disables vlan tagging
+func (rac *idrac) DisableNICVLAN(ctx context.Context) error {
+	_, err := rac.setniccfg(ctx, []string{"-v"})
+	return err
+}
@@ -1,10 +1,13 @@
 package idrac
 
-import "flag"
+import (
+	"context"
+	"flag"
+)
 
 // racresetcfg resets the idrac to factory settings.
 // https://www.dell.com/support/manuals/en-us/integrated-dell-remote-access-cntrllr-8-with-lifecycle-controller-v2.00.00.00/racadm_idrac_pub-v1/racresetcfg?guid=guid-bf4676bd-f885-4e20-a7e6-875751246867&lang=en-us
-func (rac *idrac) racresetcfg(flags []string) (execResp execResponse, err error) {
+func (rac *idrac) racresetcfg(ctx context.Context, flags []string) (execResp execResponse, err error) {
 	// parse command flags (options)
 	fs := flag.NewFlagSet("sslresetcfg", flag.ExitOnError)
 
@@ -24,7 +27,7 @@ func (rac *idrac) racresetcfg(flags []string) (execResp execResponse, err error)
 	payload.Request.UserPrivilege = 0
 
 	// execute payload
-	execResp, err = rac.executePayload(payload)
+	execResp, err = rac.executePayloadContext(ctx, payload)
 	if err != nil {
 		return execResponse{}, err
 	}
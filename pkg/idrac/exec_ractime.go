@@ -0,0 +1,192 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// racTimeLayout is the date/time part of the getractime -d / setractime -d
+// format (yyyymmddhhmmss.mmmmmm), which is followed by the utc offset in
+// minutes (sUUU, e.g. +000 or -360)
+const racTimeLayout = "20060102150405.000000"
+
+// getractime executes the getractime subcommand to display the idrac's time.
+// See getractime in the iDRAC7, iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) getractime(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	cimFormat := false
+	timezone := false
+
+	fs := flag.NewFlagSet("getractime", flag.ExitOnError)
+	fs.BoolVar(&cimFormat, "d", false, "display as yyyymmddhhmmss.mmmmmmsoff")
+	fs.BoolVar(&timezone, "z", false, "display the time zone")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	cmdInput := "racadm getractime"
+	if cimFormat {
+		cmdInput += " -d"
+	}
+	if timezone {
+		cmdInput += " -z"
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// setractime executes the setractime subcommand to set the idrac's time.
+// Usage: setractime -d yyyymmddhhmmss.mmmmmmsoff | -l yyyymmddhhmmss [-z zone]
+// See setractime in the iDRAC7, iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) setractime(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	cimTime := ""
+	localTime := ""
+	zone := ""
+
+	fs := flag.NewFlagSet("setractime", flag.ExitOnError)
+	fs.StringVar(&cimTime, "d", "", "time as yyyymmddhhmmss.mmmmmmsoff")
+	fs.StringVar(&localTime, "l", "", "local time as yyyymmddhhmmss")
+	fs.StringVar(&zone, "z", "", "time zone (e.g. US/Central) (optional, with -l)")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags and build command
+	cmdInput := ""
+	switch {
+	case cimTime != "" && localTime == "":
+		_, err = parseRACTime(cimTime)
+		if err != nil {
+			return execResponse{}, err
+		}
+		if zone != "" {
+			return execResponse{}, errors.New("-z can only be used with -l")
+		}
+		cmdInput = "racadm setractime -d " + cimTime
+
+	case localTime != "" && cimTime == "":
+		_, err = time.Parse("20060102150405", localTime)
+		if err != nil {
+			return execResponse{}, errors.New("local time (-l) must be yyyymmddhhmmss")
+		}
+		if strings.ContainsAny(zone, " \t\"") {
+			return execResponse{}, errors.New("invalid time zone")
+		}
+		cmdInput = "racadm setractime -l " + localTime
+		if zone != "" {
+			cmdInput += " -z " + zone
+		}
+
+	default:
+		return execResponse{}, errors.New("exactly one of -d or -l must be specified")
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// parseRACTime parses yyyymmddhhmmss.mmmmmmsoff (offset in minutes)
+func parseRACTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	invalid := fmt.Errorf("invalid idrac time (%s), must be yyyymmddhhmmss.mmmmmmsoff", value)
+
+	if len(value) < len(racTimeLayout)+2 {
+		return time.Time{}, invalid
+	}
+	datePart := value[:len(racTimeLayout)]
+	offsetPart := value[len(racTimeLayout):]
+
+	offsetMinutes, err := strconv.Atoi(offsetPart)
+	if err != nil || (offsetPart[0] != '+' && offsetPart[0] != '-') {
+		return time.Time{}, invalid
+	}
+
+	t, err := time.ParseInLocation(racTimeLayout, datePart, time.FixedZone("", offsetMinutes*60))
+	if err != nil {
+		return time.Time{}, invalid
+	}
+
+	return t, nil
+}
+
+// formatRACTime formats t as yyyymmddhhmmss.mmmmmmsoff
+func formatRACTime(t time.Time) string {
+	_, offsetSeconds := t.Zone()
+	offsetMinutes := offsetSeconds / 60
+
+	sign := "+"
+	if offsetMinutes < 0 {
+		sign = "-"
+		offsetMinutes = -offsetMinutes
+	}
+
+	return fmt.Sprintf("%s%s%03d", t.Format(racTimeLayout), sign, offsetMinutes)
+}
+
+// RACTime returns the idrac's current time
+func (rac *idrac) RACTime(ctx context.Context) (time.Time, error) {
+	execResp, err := rac.getractime(ctx, []string{"-d"})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return parseRACTime(execResp.Response.CommandOutput)
+}
+
+// SetRACTime sets the idrac's time to t
+func (rac *idrac) SetRACTime(ctx context.Context, t time.Time) error {
+	_, err := rac.setractime(ctx, []string{"-d", formatRACTime(t.UTC())})
+	return err
+}
+
+// ClockSkew returns how far the idrac's clock is ahead of (positive) or
+// behind (negative) the local clock. Half the round trip time is allowed
+// for, as the idrac's time is read somewhere during the request.
+func (rac *idrac) ClockSkew(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	racTime, err := rac.RACTime(ctx)
+	if err != nil {
+		return 0, err
+	}
+	rtt := time.Since(start)
+
+	localTime := start.Add(rtt / 2)
+	return racTime.Sub(localTime), nil
+}
@@ -0,0 +1,13 @@
+package idrac
+
+import (
+	"context"
+)
+
+// raid executes the raid subcommand (the iDRAC7 name of storage) using the
+// specified flags.
+// Usage: raid get controllers|vdisks|pdisks|enclosures[:fqdd] [-o] [-p property,...]
+// See raid in the iDRAC7 RACADM CLI guide.
+func (rac *idrac) raid(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	return rac.storageGet(ctx, "raid", flags)
+}
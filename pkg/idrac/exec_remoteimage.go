@@ -0,0 +1,185 @@
+package idrac
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// RemoteImageStatus is the parsed output of remoteimage -s
+type RemoteImageStatus struct {
+	Connected bool   `json:"connected"`
+	ShareName string `json:"share_name,omitempty"`
+	UserName  string `json:"username,omitempty"`
+}
+
+// remoteimage executes the remoteimage subcommand to connect, disconnect or
+// show the status of a remote (virtual media) image.
+// Usage: remoteimage -c -l <image path> [-u user] [-p password] | -d | -s
+// See remoteimage in the iDRAC7, iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) remoteimage(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	connect := false
+	disconnect := false
+	status := false
+	// the image path is given as the share (-l)
+	image := NetworkShare{}
+
+	fs := flag.NewFlagSet("remoteimage", flag.ExitOnError)
+	fs.BoolVar(&connect, "c", false, "connect the image (requires -l)")
+	fs.BoolVar(&disconnect, "d", false, "disconnect the image")
+	fs.BoolVar(&status, "s", false, "display the status")
+	fs.StringVar(&image.Path, "l", "", "image path: //server/share/image.iso (CIFS) or server:/path/image.iso (NFS)")
+	fs.StringVar(&image.Username, "u", "", "share username (optional)")
+	fs.StringVar(&image.Password, "p", "", "share password (optional)")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags (exactly one operation) and build command
+	operations := 0
+	for _, op := range []bool{connect, disconnect, status} {
+		if op {
+			operations++
+		}
+	}
+	if operations != 1 {
+		return execResponse{}, errors.New("exactly one of -c, -d, or -s must be specified")
+	}
+
+	cmdInput := ""
+	switch {
+	case connect:
+		if image.Path == "" {
+			return execResponse{}, errors.New("image path (-l) must be specified")
+		}
+		err = image.validate()
+		if err != nil {
+			return execResponse{}, err
+		}
+
+		cmdInput = "racadm remoteimage -c" + image.flags()
+
+	case disconnect, status:
+		if image != (NetworkShare{}) {
+			return execResponse{}, errInvalidOrMalpositioned
+		}
+		cmdInput = "racadm remoteimage -d"
+		if status {
+			cmdInput = "racadm remoteimage -s"
+		}
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// ParseRemoteImageStatus parses remoteimage -s output, e.g.
+// "Remote File Share is Enabled" followed by UserName and ShareName lines
+func ParseRemoteImageStatus(output string) RemoteImageStatus {
+	status := RemoteImageStatus{}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		lowerLine := strings.ToLower(line)
+
+		switch {
+		case strings.HasPrefix(lowerLine, "remote file share is"):
+			status.Connected = strings.HasSuffix(lowerLine, "enabled")
+		case strings.HasPrefix(lowerLine, "sharename"):
+			status.ShareName = strings.TrimSpace(line[len("sharename"):])
+		case strings.HasPrefix(lowerLine, "username"):
+			status.UserName = strings.TrimSpace(line[len("username"):])
+		}
+	}
+
+	return status
+}
+
+// RemoteImageStatus returns the remote image status
+func (rac *idrac) RemoteImageStatus(ctx context.Context) (RemoteImageStatus, error) {
+	execResp, err := rac.remoteimage(ctx, []string{"-s"})
+	if err != nil {
+		return RemoteImageStatus{}, err
+	}
+
+	return ParseRemoteImageStatus(execResp.Response.CommandOutput), nil
+}
+
+// ConnectRemoteImage connects the image at imagePath (CIFS or NFS) as
+// virtual media. Share credentials are optional.
+func (rac *idrac) ConnectRemoteImage(ctx context.Context, imagePath, shareUser, sharePassword string) error {
+	flags := []string{"-c", "-l", imagePath}
+	if shareUser != "" {
+		flags = append(flags, "-u", shareUser)
+	}
+	if sharePassword != "" {
+		flags = append(flags, "-p", sharePassword)
+	}
+
+	_, err := rac.remoteimage(ctx, flags)
+	if err != nil {
+		return err
+	}
+
+	// confirm
+	status, err := rac.RemoteImageStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if !status.Connected {
+		return fmt.Errorf("remote image %s did not connect", imagePath)
+	}
+
+	return nil
+}
+
+// DisconnectRemoteImage disconnects the remote image
+func (rac *idrac) DisconnectRemoteImage(ctx context.Context) error {
+	_, err := rac.remoteimage(ctx, []string{"-d"})
+	return err
+}
+
+// BootFromRemoteImage connects the image, sets the server to boot once from
+// the virtual CD and then power cycles the server (or powers it on if it is
+// off). If wait is true, it waits for the server to be on.
+func (rac *idrac) BootFromRemoteImage(ctx context.Context, imagePath, shareUser, sharePassword string, wait bool) error {
+	err := rac.ConnectRemoteImage(ctx, imagePath, shareUser, sharePassword)
+	if err != nil {
+		return fmt.Errorf("failed to connect remote image (%w)", err)
+	}
+
+	err = rac.SetNextBoot(ctx, BootDeviceVirtualCD, true)
+	if err != nil {
+		return fmt.Errorf("failed to set boot once to virtual cd (%w)", err)
+	}
+
+	state, err := rac.PowerStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if state == PowerStateOff {
+		return rac.PowerUp(ctx, wait)
+	}
+
+	return rac.PowerCycle(ctx, wait)
+}
@@ -0,0 +1,239 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// PowerState is the server's power state as reported by
+// serveraction powerstatus
+type PowerState string
+
+// known power states
+const (
+	PowerStateOn  = PowerState("ON")
+	PowerStateOff = PowerState("OFF")
+)
+
+// ServerAction is a serveraction action
+type ServerAction string
+
+// valid serveraction actions
+const (
+	ServerActionPowerStatus   = ServerAction("powerstatus")
+	ServerActionPowerUp       = ServerAction("powerup")
+	ServerActionPowerDown     = ServerAction("powerdown")
+	ServerActionPowerCycle    = ServerAction("powercycle")
+	ServerActionHardReset     = ServerAction("hardreset")
+	ServerActionGraceShutdown = ServerAction("graceshutdown")
+)
+
+// powerStatusPollInterval is how often powerstatus is polled when waiting
+// for the server to reach a power state
+const powerStatusPollInterval = 5 * time.Second
+
+// powerTransitionPollInterval and powerTransitionTimeout are how often and
+// how long powerstatus is polled for the server to go OFF during a
+// powercycle (the OFF period can be brief)
+const (
+	powerTransitionPollInterval = 1 * time.Second
+	powerTransitionTimeout      = 2 * time.Minute
+)
+
+var errInvalidServerAction = errors.New("invalid action (must be powerstatus, powerup, powerdown, powercycle, hardreset, or graceshutdown)")
+
+// expectedState returns the power state the server should reach after action
+// completes. powercycle and hardreset end with the server on (a powercycle
+// passes through OFF, see cycles; a hardreset is a warm reset and doesn't).
+func (action ServerAction) expectedState() (PowerState, error) {
+	switch action {
+	case ServerActionPowerUp, ServerActionPowerCycle, ServerActionHardReset:
+		return PowerStateOn, nil
+	case ServerActionPowerDown, ServerActionGraceShutdown:
+		return PowerStateOff, nil
+	case ServerActionPowerStatus:
+		return "", errors.New("powerstatus doesn't change the power state")
+	}
+
+	return "", errInvalidServerAction
+}
+
+// cycles returns true if action turns the server off and then back on, so
+// the server is already in its expected state before the action happens
+func (action ServerAction) cycles() bool {
+	return action == ServerActionPowerCycle
+}
+
+// serveraction executes the serveraction subcommand to control the server's
+// power using the specified flags.
+// See serveraction in the iDRAC7, iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) serveraction(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// action is positional and comes before any flags
+	if len(flags) == 0 || strings.HasPrefix(flags[0], "-") {
+		return execResponse{}, errInvalidServerAction
+	}
+	action := ServerAction(flags[0])
+	flags = flags[1:]
+
+	// parse command flags (options)
+	force := false
+
+	fs := flag.NewFlagSet("serveraction", flag.ExitOnError)
+	fs.BoolVar(&force, "f", false, "force the action (iDRAC9)")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate action
+	if action != ServerActionPowerStatus {
+		_, err = action.expectedState()
+		if err != nil {
+			return execResponse{}, err
+		}
+	}
+
+	forceParam := ""
+	if force {
+		forceParam = " -f"
+	}
+
+	// build payload to post to drac
+	cmdInput := fmt.Sprintf("racadm serveraction %s%s", action, forceParam)
+
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// PowerStatus returns the server's current power state
+func (rac *idrac) PowerStatus(ctx context.Context) (PowerState, error) {
+	execResp, err := rac.serveraction(ctx, []string{string(ServerActionPowerStatus)})
+	if err != nil {
+		return "", err
+	}
+
+	return parsePowerStatus(execResp.Response.CommandOutput)
+}
+
+// parsePowerStatus parses powerstatus output (e.g. Server power status: ON)
+func parsePowerStatus(output string) (PowerState, error) {
+	_, state, found := strings.Cut(output, ":")
+	if !found {
+		return "", fmt.Errorf("unexpected powerstatus output (%s)", strings.TrimSpace(output))
+	}
+
+	return PowerState(strings.ToUpper(strings.TrimSpace(state))), nil
+}
+
+// WaitForPowerState polls powerstatus until the server is in state or ctx is
+// done. Transient errors (e.g. the idrac being briefly busy) are ignored
+// while polling; the last one is returned if ctx ends first.
+func (rac *idrac) WaitForPowerState(ctx context.Context, state PowerState) error {
+	return rac.waitForPowerState(ctx, state, powerStatusPollInterval)
+}
+
+// waitForPowerState is WaitForPowerState, polling every interval
+func (rac *idrac) waitForPowerState(ctx context.Context, state PowerState, interval time.Duration) error {
+	var lastErr error
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		current, err := rac.PowerStatus(ctx)
+		if err == nil && current == state {
+			return nil
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("server did not reach power state %s (%w) (last error: %s)", state, ctx.Err(), lastErr)
+			}
+			return fmt.Errorf("server did not reach power state %s (%w)", state, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// ServerAction executes the power action. If wait is true, powerstatus is
+// then polled until the server reaches the action's expected state (or ctx
+// is done). For powercycle the expected state is ON, so the server is first
+// polled for OFF (within powerTransitionTimeout) before waiting for it to
+// come back ON. The OFF period can be shorter than the poll, so not seeing
+// it is only logged. A hardreset is a warm reset that powerstatus doesn't
+// show, so it only waits for ON.
+func (rac *idrac) ServerAction(ctx context.Context, action ServerAction, wait bool) error {
+	state, err := action.expectedState()
+	if err != nil {
+		return err
+	}
+
+	_, err = rac.serveraction(ctx, []string{string(action)})
+	if err != nil {
+		return err
+	}
+
+	if !wait {
+		return nil
+	}
+
+	// the server is already ON, so wait for it to go OFF first
+	if action.cycles() {
+		offCtx, cancel := context.WithTimeout(ctx, powerTransitionTimeout)
+		err = rac.waitForPowerState(offCtx, PowerStateOff, powerTransitionPollInterval)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			log.Printf("%s: server was not seen to power off within %s, waiting for it to be on", action, powerTransitionTimeout)
+		}
+	}
+
+	return rac.WaitForPowerState(ctx, state)
+}
+
+// PowerUp powers the server on
+func (rac *idrac) PowerUp(ctx context.Context, wait bool) error {
+	return rac.ServerAction(ctx, ServerActionPowerUp, wait)
+}
+
+// PowerDown powers the server off (not gracefully)
+func (rac *idrac) PowerDown(ctx context.Context, wait bool) error {
+	return rac.ServerAction(ctx, ServerActionPowerDown, wait)
+}
+
+// PowerCycle powers the server off and then back on
+func (rac *idrac) PowerCycle(ctx context.Context, wait bool) error {
+	return rac.ServerAction(ctx, ServerActionPowerCycle, wait)
+}
+
+// HardReset resets the server
+func (rac *idrac) HardReset(ctx context.Context, wait bool) error {
+	return rac.ServerAction(ctx, ServerActionHardReset, wait)
+}
+
+// GraceShutdown asks the operating system to shut down the server
+func (rac *idrac) GraceShutdown(ctx context.Context, wait bool) error {
+	return rac.ServerAction(ctx, ServerActionGraceShutdown, wait)
+}
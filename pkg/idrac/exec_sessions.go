@@ -0,0 +1,209 @@
+package idrac
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Session is an active idrac session as reported by getssninfo
+type Session struct {
+	ID        int       `json:"id"`
+	Type      string    `json:"type"`
+	User      string    `json:"user"`
+	IPAddress string    `json:"ip_address"`
+	LoginTime time.Time `json:"login_time"`
+	DateTime  string    `json:"date_time"`
+}
+
+// getssninfo executes the getssninfo subcommand to list active sessions.
+// See getssninfo in the iDRAC7, iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) getssninfo(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	user := ""
+	noHeaders := false
+
+	fs := flag.NewFlagSet("getssninfo", flag.ExitOnError)
+	fs.StringVar(&user, "u", "", "only display sessions of this user (optional)")
+	fs.BoolVar(&noHeaders, "A", false, "do not print headers or labels")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags and build command
+	if strings.ContainsAny(user, " \t\"") {
+		return execResponse{}, errors.New("invalid user")
+	}
+
+	cmdInput := "racadm getssninfo"
+	if user != "" {
+		cmdInput += " -u " + user
+	}
+	if noHeaders {
+		cmdInput += " -A"
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// Sessions returns the active sessions
+func (rac *idrac) Sessions(ctx context.Context) ([]Session, error) {
+	execResp, err := rac.getssninfo(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseSessions(execResp.Response.CommandOutput), nil
+}
+
+// ParseSessions parses the getssninfo table. Rows are: id, type (which may
+// contain spaces), user, ip address and login date/time. Header and
+// separator rows are skipped.
+func ParseSessions(output string) []Session {
+	sessions := []Session{}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+
+		id, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+
+		// find the ip address column; type and user are before it
+		ipIndex := -1
+		for i := 2; i < len(fields); i++ {
+			if net.ParseIP(fields[i]) != nil || strings.EqualFold(fields[i], "N/A") {
+				ipIndex = i
+				break
+			}
+		}
+		if ipIndex < 0 {
+			continue
+		}
+
+		session := Session{
+			ID:        id,
+			Type:      strings.Join(fields[1:ipIndex-1], " "),
+			User:      fields[ipIndex-1],
+			IPAddress: fields[ipIndex],
+			DateTime:  strings.Join(fields[ipIndex+1:], " "),
+		}
+		session.LoginTime = parseLogTime(session.DateTime)
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions
+}
+
+// closessn executes the closessn subcommand to close sessions.
+// Usage: closessn -i <id> | -a | -u <user>
+// See closessn in the iDRAC7, iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) closessn(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	id := 0
+	all := false
+	user := ""
+
+	fs := flag.NewFlagSet("closessn", flag.ExitOnError)
+	fs.IntVar(&id, "i", 0, "id of the session to close")
+	fs.BoolVar(&all, "a", false, "close all sessions (except the current one)")
+	fs.StringVar(&user, "u", "", "close all sessions of this user")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags (exactly one option) and build command
+	optionCount := 0
+	cmdInput := "racadm closessn"
+	if id != 0 {
+		optionCount++
+		cmdInput += fmt.Sprintf(" -i %d", id)
+	}
+	if all {
+		optionCount++
+		cmdInput += " -a"
+	}
+	if user != "" {
+		optionCount++
+		cmdInput += " -u " + user
+	}
+	if optionCount != 1 {
+		return execResponse{}, errors.New("exactly one of -i, -a, or -u must be specified")
+	}
+	if id < 0 || strings.ContainsAny(user, " \t\"") {
+		return execResponse{}, errors.New("invalid session id or user")
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// CloseSession closes the session with the specified id
+func (rac *idrac) CloseSession(ctx context.Context, id int) error {
+	_, err := rac.closessn(ctx, []string{"-i", strconv.Itoa(id)})
+	return err
+}
+
+// CloseAllSessions closes all sessions other than the current one
+func (rac *idrac) CloseAllSessions(ctx context.Context) error {
+	_, err := rac.closessn(ctx, []string{"-a"})
+	return err
+}
+
+// CloseUserSessions closes all sessions of user
+func (rac *idrac) CloseUserSessions(ctx context.Context, user string) error {
+	_, err := rac.closessn(ctx, []string{"-u", user})
+	return err
+}
+
+// SetCloseStaleSessions sets whether Login, when the idrac refuses it (e.g.
+// because the session limit is reached by sessions left behind by runs that
+// failed before logging out), closes this user's sessions opened from this
+// host and retries. Sessions are found and closed with Redfish, which
+// doesn't need a session.
+func (rac *idrac) SetCloseStaleSessions(enabled bool) {
+	rac.closeStaleSessions = enabled
+}
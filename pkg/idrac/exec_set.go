@@ -0,0 +1,130 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+var errValueRequired = errors.New("attribute value must be specified")
+
+// SetResult describes the outcome of setting an attribute. Some attributes
+// (e.g. BIOS, NIC, RAID) are only staged by set and need a Lifecycle
+// Controller job (and possibly a host reboot) before they are applied.
+type SetResult struct {
+	Key            string `json:"key"`
+	Value          string `json:"value"`
+	JobRequired    bool   `json:"job_required"`
+	RebootRequired bool   `json:"reboot_required"`
+	Message        string `json:"message"`
+}
+
+// set executes the set subcommand to write an attribute by dotted key, or
+// with -f and -t to import a Server Configuration Profile (from a local
+// file, or a network share with -l).
+// Usage: set <key> <value> | set -f <file> -t xml|json [-b Graceful|Forced|NoReboot]
+// [-w minutes] [-s On|Off] [-l share [-u user] [-p password]]
+// See set in the iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) set(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// profile import is all flags
+	if len(flags) > 0 && strings.HasPrefix(flags[0], "-") {
+		return rac.setSCPFlags(ctx, flags)
+	}
+
+	// key and value are positional and come before any flags
+	if len(flags) == 0 {
+		return execResponse{}, errKeyRequired
+	}
+	if len(flags) < 2 {
+		return execResponse{}, errValueRequired
+	}
+	key := flags[0]
+	value := flags[1]
+	flags = flags[2:]
+
+	// parse command flags (options)
+	fs := flag.NewFlagSet("set", flag.ContinueOnError)
+
+	// no flags currently supported with a key
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate key and value
+	if strings.ContainsAny(key, " \t\"") {
+		return execResponse{}, fmt.Errorf("invalid attribute key (%s)", key)
+	}
+	if strings.ContainsAny(value, "\"\r\n") {
+		return execResponse{}, errors.New("attribute value must not contain quotes or new lines")
+	}
+
+	// quote value if it contains whitespace (or is empty)
+	if value == "" || strings.ContainsAny(value, " \t") {
+		value = "\"" + value + "\""
+	}
+
+	// build payload to post to drac
+	cmdInput := fmt.Sprintf("racadm set %s %s", key, value)
+
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// Set writes value to the attribute at the dotted key and reports whether
+// a job and/or reboot is required for the new value to take effect.
+func (rac *idrac) Set(ctx context.Context, key, value string) (SetResult, error) {
+	// a leading - would be parsed as a profile import flag
+	if strings.HasPrefix(key, "-") {
+		return SetResult{}, fmt.Errorf("invalid attribute key (%s)", key)
+	}
+
+	execResp, err := rac.set(ctx, []string{key, value})
+	if err != nil {
+		return SetResult{}, err
+	}
+
+	return parseSetResult(key, value, execResp.Response.CommandOutput), nil
+}
+
+// parseSetResult interprets the output of set. RAC1017 indicates the value is
+// pending and a configuration job must be created to apply it.
+func parseSetResult(key, value, output string) SetResult {
+	result := SetResult{
+		Key:     key,
+		Value:   value,
+		Message: strings.TrimSpace(output),
+	}
+
+	lowerOutput := strings.ToLower(output)
+	if strings.Contains(output, "RAC1017") || strings.Contains(lowerOutput, "pending state") {
+		result.JobRequired = true
+
+		// host side components only apply on reboot; iDRAC and Lifecycle
+		// Controller attributes do not need the server to reboot
+		lowerKey := strings.ToLower(key)
+		if !strings.HasPrefix(lowerKey, "idrac.") && !strings.HasPrefix(lowerKey, "lifecyclecontroller.") {
+			result.RebootRequired = true
+		}
+	}
+	if strings.Contains(lowerOutput, "reboot") && strings.Contains(lowerOutput, "required") {
+		result.RebootRequired = true
+	}
+
+	return result
+}
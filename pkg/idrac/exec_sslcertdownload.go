@@ -1,10 +1,13 @@
 package idrac
 
 import (
+	"context"
+	"encoding/pem"
 	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 )
 
 // sslcertdownload executes the sslcertdownload subcommand using
@@ -87,3 +90,26 @@ func (rac *idrac) sslcertdownload(flags []string) (execResp execResponse, err er
 
 	return execResp, nil
 }
+
+// WebCertificate returns the idrac's installed web server certificate (pem)
+func (rac *idrac) WebCertificate(ctx context.Context) (string, error) {
+	payload := execPayload{}
+	payload.Request.CommandInput = "racadm sslcertdownload -f sslcertfile -t 1"
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	execResp, err := rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return "", err
+	}
+
+	// confirm output is a pem certificate
+	certPem := strings.TrimSpace(execResp.Response.CommandOutput)
+	block, _ := pem.Decode([]byte(certPem))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return "", errors.New("web server certificate download did not return a pem certificate")
+	}
+
+	return certPem + "\n", nil
+}
@@ -1,13 +1,14 @@
 package idrac
 
 import (
+	"context"
 	"flag"
 )
 
 // racreset resets the idrac using the specified flags.
 // https://www.dell.com/support/manuals/en-us/poweredge-m630/idrac8_2.70.70.70_racadm/racreset?guid=guid-7866bef3-f5c4-4c8b-b2e3-ce22d6332ddc&lang=en-us
 // https://www.dell.com/support/manuals/en-us/idrac9-lifecycle-controller-v5.x-series/idrac9_5.xx_racadm_pub/racreset?guid=guid-a5b943ea-b4b5-415a-bd3c-09a02dfed465&lang=en-us
-func (rac *idrac) sslresetcfg(flags []string) (execResp execResponse, err error) {
+func (rac *idrac) sslresetcfg(ctx context.Context, flags []string) (execResp execResponse, err error) {
 	// parse command flags (options)
 	fs := flag.NewFlagSet("sslresetcfg", flag.ExitOnError)
 
@@ -29,7 +30,7 @@ func (rac *idrac) sslresetcfg(flags []string) (execResp execResponse, err error)
 	payload.Request.UserPrivilege = 0
 
 	// execute payload
-	execResp, err = rac.executePayload(payload)
+	execResp, err = rac.executePayloadContext(ctx, payload)
 	if err != nil {
 		return execResponse{}, err
 	}
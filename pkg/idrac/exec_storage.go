@@ -0,0 +1,346 @@
+package idrac
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// storageObjectTypes are the object types storage (and raid) get reports
+var storageObjectTypes = []string{"controllers", "vdisks", "pdisks", "enclosures"}
+
+// StorageObject is a single fqdd block of storage (or raid) get output.
+// Properties holds every property as reported.
+type StorageObject struct {
+	FQDD         string            `json:"fqdd"`
+	Name         string            `json:"name,omitempty"`
+	Status       string            `json:"status,omitempty"`
+	RollupStatus string            `json:"rollup_status,omitempty"`
+	State        string            `json:"state,omitempty"`
+	Properties   map[string]string `json:"properties"`
+}
+
+// prop returns the value of the first of keys present in Properties
+func (obj StorageObject) prop(keys ...string) string {
+	for _, key := range keys {
+		if value, ok := obj.Properties[key]; ok {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// Healthy returns true unless the object's status or rollup status (its
+// own health and that of its children) reports a problem. An object whose
+// health wasn't reported (e.g. not selected with -p) is considered healthy.
+func (obj StorageObject) Healthy() bool {
+	for _, status := range []string{obj.Status, obj.RollupStatus} {
+		if status != "" && !strings.EqualFold(status, "Ok") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// StorageController is a RAID (or other storage) controller
+type StorageController struct {
+	StorageObject
+	FirmwareVersion string `json:"firmware_version"`
+	DriverVersion   string `json:"driver_version"`
+	CacheMemorySize string `json:"cache_memory_size"`
+}
+
+// VirtualDisk is a virtual disk (array)
+type VirtualDisk struct {
+	StorageObject
+	Layout        string   `json:"layout"`
+	Size          string   `json:"size"`
+	PhysicalDisks []string `json:"physical_disks,omitempty"`
+	// Degraded is true if the virtual disk has lost redundancy or failed
+	Degraded bool `json:"degraded"`
+}
+
+// PhysicalDisk is a physical disk attached to a controller
+type PhysicalDisk struct {
+	StorageObject
+	Model        string `json:"model"`
+	SerialNumber string `json:"serial_number"`
+	Size         string `json:"size"`
+	MediaType    string `json:"media_type"`
+	BusProtocol  string `json:"bus_protocol"`
+	// PredictiveFailure is true if the disk reports it is expected to fail
+	PredictiveFailure bool `json:"predictive_failure"`
+}
+
+// Enclosure is a storage enclosure (backplane)
+type Enclosure struct {
+	StorageObject
+	FirmwareVersion string `json:"firmware_version"`
+	SlotCount       string `json:"slot_count"`
+}
+
+// StorageInventory is the parsed output of storage (or raid) get, by object
+// type
+type StorageInventory struct {
+	Controllers   []StorageController `json:"controllers"`
+	VirtualDisks  []VirtualDisk       `json:"vdisks"`
+	PhysicalDisks []PhysicalDisk      `json:"pdisks"`
+	Enclosures    []Enclosure         `json:"enclosures"`
+}
+
+// Problems returns a description of each degraded or unhealthy object and
+// each disk predicted to fail, or none if storage is healthy
+func (inv StorageInventory) Problems() []string {
+	problems := []string{}
+	for _, c := range inv.Controllers {
+		if !c.Healthy() {
+			problems = append(problems, fmt.Sprintf("controller %s status is %s (rollup %s)", c.FQDD, c.Status, c.RollupStatus))
+		}
+	}
+	for _, vd := range inv.VirtualDisks {
+		if vd.Degraded || !vd.Healthy() {
+			problems = append(problems, fmt.Sprintf("virtual disk %s (%s) is %s, status %s", vd.FQDD, vd.Layout, vd.State, vd.Status))
+		}
+	}
+	for _, pd := range inv.PhysicalDisks {
+		if pd.PredictiveFailure {
+			problems = append(problems, fmt.Sprintf("physical disk %s (serial %s) is predicted to fail", pd.FQDD, pd.SerialNumber))
+		}
+		if !pd.Healthy() {
+			problems = append(problems, fmt.Sprintf("physical disk %s is %s, status %s", pd.FQDD, pd.State, pd.Status))
+		}
+	}
+	for _, e := range inv.Enclosures {
+		if !e.Healthy() {
+			problems = append(problems, fmt.Sprintf("enclosure %s status is %s (rollup %s)", e.FQDD, e.Status, e.RollupStatus))
+		}
+	}
+
+	return problems
+}
+
+// storage executes the storage subcommand using the specified flags. Only
+// get is supported.
+// Usage: storage get controllers|vdisks|pdisks|enclosures[:fqdd] [-o] [-p property,...]
+// See storage in the iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) storage(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	return rac.storageGet(ctx, "storage", flags)
+}
+
+// storageGet executes get of storage or raid (command), which share their
+// syntax
+func (rac *idrac) storageGet(ctx context.Context, command string, flags []string) (execResp execResponse, err error) {
+	// operation and object are positional
+	if len(flags) < 2 || flags[0] != "get" {
+		return execResponse{}, fmt.Errorf("%s get and the object type (controllers, vdisks, pdisks, or enclosures) must be specified", command)
+	}
+	object := flags[1]
+	flags = flags[2:]
+
+	objectType, fqdd, _ := strings.Cut(object, ":")
+	valid := false
+	for _, t := range storageObjectTypes {
+		if objectType == t {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return execResponse{}, fmt.Errorf("invalid %s object type %s (must be controllers, vdisks, pdisks, or enclosures)", command, objectType)
+	}
+
+	// parse command flags (options)
+	details := false
+	properties := ""
+
+	fs := flag.NewFlagSet(command+" get", flag.ExitOnError)
+	fs.BoolVar(&details, "o", false, "show object properties")
+	fs.StringVar(&properties, "p", "", "comma separated properties to show (implies -o)")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags and build command
+	if strings.ContainsAny(fqdd+properties, " \t\"") {
+		return execResponse{}, errors.New("fqdd and properties must not contain spaces or quotes")
+	}
+	if object != objectType && fqdd == "" {
+		return execResponse{}, errors.New("fqdd must not be empty")
+	}
+
+	cmdInput := fmt.Sprintf("racadm %s get %s", command, object)
+	if details || properties != "" {
+		cmdInput += " -o"
+	}
+	if properties != "" {
+		cmdInput += " -p " + properties
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = maxOutputLenLarge
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	return rac.executePayloadContext(ctx, payload)
+}
+
+// ParseStorage parses storage (or raid) get -o output. Each object starts
+// with an unindented fqdd line followed by indented Key = Value lines
+// (without -o, only the fqdd lines). Objects are typed by their fqdd.
+func ParseStorage(output string) StorageInventory {
+	objs := []StorageObject{}
+
+	var obj *StorageObject
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		rawLine := scanner.Text()
+		line := strings.TrimSpace(rawLine)
+		if line == "" {
+			continue
+		}
+
+		// new object
+		if rawLine == line && !strings.Contains(line, "=") {
+			objs = append(objs, StorageObject{
+				FQDD:       line,
+				Properties: make(map[string]string),
+			})
+			obj = &objs[len(objs)-1]
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found || obj == nil {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		obj.Properties[key] = value
+		switch key {
+		case "Name":
+			obj.Name = value
+		case "Status":
+			obj.Status = value
+		case "RollupStatus":
+			obj.RollupStatus = value
+		case "State":
+			obj.State = value
+		}
+	}
+
+	inv := StorageInventory{}
+	for _, obj := range objs {
+		inv.add(obj)
+	}
+
+	return inv
+}
+
+// add adds obj to the appropriate typed list, based on its fqdd (e.g.
+// Disk.Virtual.0:RAID.Integrated.1-1, Disk.Bay.0:Enclosure.Internal.0-1:...,
+// Enclosure.Internal.0-1:RAID.Integrated.1-1 or RAID.Integrated.1-1)
+func (inv *StorageInventory) add(obj StorageObject) {
+	switch {
+	case strings.HasPrefix(obj.FQDD, "Disk.Virtual."):
+		vd := VirtualDisk{
+			StorageObject: obj,
+			Layout:        obj.prop("Layout"),
+			Size:          obj.prop("Size"),
+		}
+		for _, pd := range strings.Split(obj.prop("PhysicalDisks"), ",") {
+			if pd = strings.TrimSpace(pd); pd != "" {
+				vd.PhysicalDisks = append(vd.PhysicalDisks, pd)
+			}
+		}
+		state := strings.ToLower(obj.State)
+		vd.Degraded = strings.Contains(state, "degraded") || strings.Contains(state, "failed") || strings.Contains(state, "offline")
+		inv.VirtualDisks = append(inv.VirtualDisks, vd)
+
+	case strings.HasPrefix(obj.FQDD, "Disk."):
+		inv.PhysicalDisks = append(inv.PhysicalDisks, PhysicalDisk{
+			StorageObject:     obj,
+			Model:             obj.prop("Model", "ProductId"),
+			SerialNumber:      obj.prop("SerialNumber"),
+			Size:              obj.prop("Size"),
+			MediaType:         obj.prop("MediaType"),
+			BusProtocol:       obj.prop("BusProtocol"),
+			PredictiveFailure: strings.EqualFold(obj.prop("FailurePredicted"), "YES"),
+		})
+
+	case strings.HasPrefix(obj.FQDD, "Enclosure."):
+		inv.Enclosures = append(inv.Enclosures, Enclosure{
+			StorageObject:   obj,
+			FirmwareVersion: obj.prop("FirmwareVersion"),
+			SlotCount:       obj.prop("SlotCount"),
+		})
+
+	default:
+		inv.Controllers = append(inv.Controllers, StorageController{
+			StorageObject:   obj,
+			FirmwareVersion: obj.prop("FirmwareVersion"),
+			DriverVersion:   obj.prop("DriverVersion"),
+			CacheMemorySize: obj.prop("CacheMemorySize"),
+		})
+	}
+}
+
+// Storage returns the storage controllers, virtual and physical disks and
+// enclosures with their properties, using storage (iDRAC8/9) or raid
+// (iDRAC7). If any output is truncated, ErrOutputTruncated is returned
+// rather than a partial inventory.
+func (rac *idrac) Storage(ctx context.Context) (StorageInventory, error) {
+	inv := StorageInventory{}
+	for _, objectType := range storageObjectTypes {
+		execResp, err := rac.storageGetObjects(ctx, objectType)
+		if err != nil {
+			return StorageInventory{}, err
+		}
+
+		objs := ParseStorage(execResp.Response.CommandOutput)
+		inv.Controllers = append(inv.Controllers, objs.Controllers...)
+		inv.VirtualDisks = append(inv.VirtualDisks, objs.VirtualDisks...)
+		inv.PhysicalDisks = append(inv.PhysicalDisks, objs.PhysicalDisks...)
+		inv.Enclosures = append(inv.Enclosures, objs.Enclosures...)
+	}
+
+	return inv, nil
+}
+
+// storageGetObjects gets every object of objectType with its properties.
+// The first call tries storage and falls back to raid (iDRAC7), then the
+// subcommand that worked is used from then on.
+func (rac *idrac) storageGetObjects(ctx context.Context, objectType string) (execResponse, error) {
+	flags := []string{"get", objectType, "-o"}
+	if rac.storageCommand != "" {
+		return rac.storageGet(ctx, rac.storageCommand, flags)
+	}
+
+	execResp, err := rac.storageGet(ctx, "storage", flags)
+	if err == nil {
+		rac.storageCommand = "storage"
+		return execResp, nil
+	}
+	// storage worked, its output just didn't fit
+	if errors.Is(err, ErrOutputTruncated) {
+		return execResponse{}, err
+	}
+
+	execResp, raidErr := rac.storageGet(ctx, "raid", flags)
+	if raidErr != nil {
+		return execResponse{}, fmt.Errorf("storage get failed (%w) and raid get failed (%w)", err, raidErr)
+	}
+	rac.storageCommand = "raid"
+	return execResp, nil
+}
@@ -0,0 +1,233 @@
+package idrac
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// TSRSelector selects the data a technical support report collects
+type TSRSelector string
+
+// valid data selectors (techsupreport collect -t)
+const (
+	TSRSysInfo    = TSRSelector("SysInfo")
+	TSRTTYLog     = TSRSelector("TTYLog")
+	TSROSAppAll   = TSRSelector("OSAppAll")
+	TSROSAppNoPII = TSRSelector("OSAppNoPII")
+	TSRDebug      = TSRSelector("Debug")
+)
+
+// tsrSelectors is every valid TSRSelector
+var tsrSelectors = []TSRSelector{TSRSysInfo, TSRTTYLog, TSROSAppAll, TSROSAppNoPII, TSRDebug}
+
+// errTSRShareRequired is returned for an export without a network share.
+// Local export isn't implemented yet. Profiles (get -f) and licenses are
+// text, so their local export is taken from the command output, but a
+// report is a zip archive, whose bytes can't be carried in the exec
+// response's xml text. It needs the binary file retrieval racadm uses,
+// which (unlike putfile for uploads) this package doesn't have yet.
+var errTSRShareRequired = errors.New("technical support report local export is not implemented yet, export to a network share (-l)")
+
+// parseTSRSelectors validates a comma separated -t value, returning it with
+// each selector's case corrected
+func parseTSRSelectors(value string) (string, error) {
+	selectors := []string{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+
+		valid := false
+		for _, selector := range tsrSelectors {
+			if strings.EqualFold(name, string(selector)) {
+				selectors = append(selectors, string(selector))
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return "", fmt.Errorf("invalid data selector %s (must be SysInfo, TTYLog, OSAppAll, OSAppNoPII, or Debug)", name)
+		}
+	}
+
+	return strings.Join(selectors, ","), nil
+}
+
+// techsupreport executes the techsupreport subcommand to collect a technical
+// support report (TSR), show when its data was last updated, or export it
+// to a network share (local export isn't implemented yet, see
+// errTSRShareRequired).
+// Usage: techsupreport collect [-t SysInfo,TTYLog,OSAppAll,OSAppNoPII,Debug]
+// techsupreport getupdatetime
+// techsupreport export -f <file> -l share [-u user] [-p password]
+// See techsupreport in the iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) techsupreport(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// operation is positional
+	if len(flags) == 0 {
+		return execResponse{}, errors.New("techsupreport operation (collect, getupdatetime, or export) must be specified")
+	}
+	operation := flags[0]
+	flags = flags[1:]
+
+	// parse command flags (options)
+	selectors := ""
+	file := ""
+	share := NetworkShare{}
+
+	fs := flag.NewFlagSet("techsupreport "+operation, flag.ExitOnError)
+	switch operation {
+	case "collect":
+		fs.StringVar(&selectors, "t", "", "comma separated data selectors (optional, default SysInfo and TTYLog)")
+	case "getupdatetime":
+		// no flags
+	case "export":
+		fs.StringVar(&file, "f", "", "file name to export to on the share (required)")
+		fs.StringVar(&share.Path, "l", "", "network share to export to, e.g. //server/share or server:/path (required)")
+		fs.StringVar(&share.Username, "u", "", "network share username (optional)")
+		fs.StringVar(&share.Password, "p", "", "network share password (optional)")
+	default:
+		return execResponse{}, fmt.Errorf("invalid techsupreport operation %s (must be collect, getupdatetime, or export)", operation)
+	}
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate command flags and build command
+	cmdInput := "racadm techsupreport " + operation
+	switch operation {
+	case "collect":
+		if selectors != "" {
+			selectors, err = parseTSRSelectors(selectors)
+			if err != nil {
+				return execResponse{}, err
+			}
+			cmdInput += " -t " + selectors
+		}
+
+	case "export":
+		if file == "" {
+			return execResponse{}, errors.New("file name (-f) must be specified")
+		}
+		if share.Path == "" {
+			return execResponse{}, errTSRShareRequired
+		}
+		err = share.validate()
+		if err != nil {
+			return execResponse{}, err
+		}
+		if strings.ContainsAny(file, " \t\"/\\") {
+			return execResponse{}, errors.New("file name (-f) must not contain spaces, quotes, or slashes when exporting to a share")
+		}
+		cmdInput += " -f " + file + share.flags()
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = cmdInput
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// CollectTSR collects a technical support report with the selected data
+// (the idrac's default if none) and waits for the collection job. The
+// report stays on the idrac until exported.
+func (rac *idrac) CollectTSR(ctx context.Context, selectors []TSRSelector) (Job, error) {
+	flags := []string{"collect"}
+	if len(selectors) > 0 {
+		names := []string{}
+		for _, selector := range selectors {
+			names = append(names, string(selector))
+		}
+		flags = append(flags, "-t", strings.Join(names, ","))
+	}
+
+	execResp, err := rac.techsupreport(ctx, flags)
+	if err != nil {
+		return Job{}, err
+	}
+
+	jobIDs := parseJobIDs(execResp.Response.CommandOutput)
+	if len(jobIDs) == 0 {
+		return Job{}, fmt.Errorf("report collection did not return a job id (%s)", strings.TrimSpace(execResp.Response.CommandOutput))
+	}
+
+	return rac.WaitForJob(ctx, jobIDs[0])
+}
+
+// ParseTSRUpdateTimes parses techsupreport getupdatetime output (lines such
+// as "The last system inventory update time is 2017-05-31 10:23:10") into a
+// map of data description (e.g. "system inventory") to time
+func ParseTSRUpdateTimes(output string) map[string]string {
+	times := make(map[string]string)
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSuffix(strings.TrimSpace(scanner.Text()), ".")
+
+		description, value, found := strings.Cut(line, " time is ")
+		if !found {
+			description, value, found = strings.Cut(line, ":")
+		}
+		if !found {
+			continue
+		}
+
+		description = strings.TrimPrefix(strings.TrimSpace(description), "The last ")
+		description = strings.TrimSuffix(strings.TrimSuffix(description, " update"), " collection")
+		times[strings.ToLower(description)] = strings.TrimSpace(value)
+	}
+
+	return times
+}
+
+// TSRUpdateTimes returns when each kind of report data was last updated
+func (rac *idrac) TSRUpdateTimes(ctx context.Context) (map[string]string, error) {
+	execResp, err := rac.techsupreport(ctx, []string{"getupdatetime"})
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseTSRUpdateTimes(execResp.Response.CommandOutput), nil
+}
+
+// ExportTSRToShare exports the collected report to file on the network
+// share. If the export runs as a job, it waits for the job. This is the only
+// way to export a report (see errTSRShareRequired).
+func (rac *idrac) ExportTSRToShare(ctx context.Context, file string, share NetworkShare) error {
+	if share.Path == "" {
+		return errors.New("network share must be specified")
+	}
+
+	flags := []string{"export", "-f", file, "-l", share.Path}
+	if share.Username != "" {
+		flags = append(flags, "-u", share.Username)
+	}
+	if share.Password != "" {
+		flags = append(flags, "-p", share.Password)
+	}
+
+	execResp, err := rac.techsupreport(ctx, flags)
+	if err != nil {
+		return err
+	}
+
+	jobIDs := parseJobIDs(execResp.Response.CommandOutput)
+	if len(jobIDs) > 0 {
+		_, err = rac.WaitForJob(ctx, jobIDs[0])
+	}
+	return err
+}
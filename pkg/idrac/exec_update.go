@@ -0,0 +1,79 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// update executes the update subcommand (iDRAC8 and newer) to update
+// firmware from a local image (firmimg.d7, firmimg.d9, or a Dell Update
+// Package). The output includes the id of the update job.
+// Usage: update -f <local image file>
+// See update in the iDRAC8 and iDRAC9 RACADM CLI guides.
+func (rac *idrac) update(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	imagePath := ""
+
+	fs := flag.NewFlagSet("update", flag.ExitOnError)
+	fs.StringVar(&imagePath, "f", "", "local firmware image file (required)")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		log.Println("-l, -u, -p, -e, -t, and --reboot are not currently supported by goracadm")
+		return execResponse{}, err
+	}
+
+	// validate command flags
+	if imagePath == "" {
+		return execResponse{}, errors.New("image file (-f) must be specified")
+	}
+
+	content, err := os.ReadFile(imagePath)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	fileName := filepath.Base(imagePath)
+	err = checkFirmwareImage(fileName, content, false)
+	if err != nil {
+		return execResponse{}, err
+	}
+	err = rac.checkFirmwareImageTarget(ctx, fileName)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// put the image on the rac (name keeps the extension, which tells the
+	// idrac the image format)
+	remoteFile := "firmimg" + strings.ToLower(filepath.Ext(fileName))
+	err = rac.putfileContext(ctx, putfilePayload{
+		filename: remoteFile,
+		flags:    0,
+		content:  content,
+		binary:   true,
+	})
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = "racadm update -f " + remoteFile
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
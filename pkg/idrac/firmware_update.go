@@ -0,0 +1,284 @@
+package idrac
+
+import (
+	"bytes"
+	"context"
+	"debug/pe"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// minFirmwareImageSize is well under the size of any real idrac image,
+	// it only catches truncated downloads and error pages
+	minFirmwareImageSize   = 1 << 20
+	firmwareUpdatePoll     = 10 * time.Second
+	firmwareResetStartWait = 5 * time.Minute
+	// peSecurityDirectory is the index of the (Authenticode) certificate
+	// table in a PE file's data directories
+	peSecurityDirectory = 4
+)
+
+// checkFirmwareImage does sanity checks of a firmware image before it is
+// put on the idrac: the extension must be one the update method accepts
+// (legacy fwupdate only takes .d7), the content must be a plausible size
+// and not an archive or text (e.g. an html error page saved by a browser),
+// and a Dell Update Package must be a signed PE executable. The idrac
+// verifies the image itself; this is only to fail early and clearly.
+func checkFirmwareImage(name string, content []byte, legacy bool) error {
+	ext := strings.ToLower(filepath.Ext(name))
+	switch {
+	case legacy && ext != ".d7":
+		return fmt.Errorf("firmware image %s must be a .d7 image for this idrac", name)
+	case !legacy && ext != ".d7" && ext != ".d9" && ext != ".exe":
+		return fmt.Errorf("firmware image %s must be a .d7, .d9, or .exe (Dell Update Package) file", name)
+	}
+
+	if len(content) < minFirmwareImageSize {
+		return fmt.Errorf("firmware image %s is too small (%d bytes) to be valid", name, len(content))
+	}
+
+	trimmed := bytes.TrimSpace(content[:512])
+	switch {
+	case bytes.HasPrefix(content, []byte("PK\x03\x04")):
+		return fmt.Errorf("firmware image %s is a zip archive, extract the image from it first", name)
+	case bytes.HasPrefix(trimmed, []byte("<")) || bytes.HasPrefix(trimmed, []byte("-----BEGIN")):
+		return fmt.Errorf("firmware image %s is a text file, not a firmware image", name)
+	}
+
+	isPE := bytes.HasPrefix(content, []byte("MZ"))
+	if ext != ".exe" {
+		if isPE {
+			return fmt.Errorf("firmware image %s is an executable, use the .exe extension for a Dell Update Package", name)
+		}
+		return nil
+	}
+
+	// Dell Update Package
+	if !isPE {
+		return fmt.Errorf("firmware image %s is not a Dell Update Package executable", name)
+	}
+	f, err := pe.NewFile(bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("firmware image %s is not a valid Dell Update Package (%w)", name, err)
+	}
+	defer f.Close()
+
+	signed := false
+	switch header := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		signed = header.NumberOfRvaAndSizes > peSecurityDirectory && header.DataDirectory[peSecurityDirectory].Size > 0
+	case *pe.OptionalHeader64:
+		signed = header.NumberOfRvaAndSizes > peSecurityDirectory && header.DataDirectory[peSecurityDirectory].Size > 0
+	}
+	if !signed {
+		return fmt.Errorf("firmware image %s is not signed", name)
+	}
+
+	return nil
+}
+
+// checkFirmwareImageTarget confirms the image format matches the idrac's
+// generation, when its firmware version can be determined (iDRAC9 is 3.x
+// and newer and takes .d9 images, iDRAC7/8 are 2.x and older and take .d7
+// images)
+func (rac *idrac) checkFirmwareImageTarget(ctx context.Context, name string) error {
+	version, err := rac.FirmwareVersion(ctx)
+	if err != nil {
+		log.Printf("unable to determine idrac firmware version, image generation not checked (%s)", err)
+		return nil
+	}
+
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext == ".d7" && version.Major >= 3 {
+		return fmt.Errorf("firmware image %s is for iDRAC7/8 but idrac firmware is %s (iDRAC9)", name, version)
+	}
+	if ext == ".d9" && version.Major < 3 {
+		return fmt.Errorf("firmware image %s is for iDRAC9 but idrac firmware is %s", name, version)
+	}
+
+	return nil
+}
+
+// UpdateFirmware updates the idrac firmware from the local image file, using
+// fwupdate (iDRAC7 and older) or update (iDRAC8 and newer). Progress is
+// logged while polling. The idrac resets itself to finish the update, so
+// after the update completes this waits for the reset and logs back in.
+// The firmware version reported after the reset is returned; if it is the
+// same as before the update, the update didn't take and an error is
+// returned. ctx should allow for the whole update (typically 10 to 30 minutes).
+func (rac *idrac) UpdateFirmware(ctx context.Context, imagePath string) (FirmwareVersion, error) {
+	err := rac.checkFirmwareImageTarget(ctx, filepath.Base(imagePath))
+	if err != nil {
+		return FirmwareVersion{}, err
+	}
+	oldVersion, _ := rac.FirmwareVersion(ctx)
+
+	if rac.usesAttributes(ctx) {
+		err = rac.updateFirmwareJob(ctx, imagePath)
+	} else {
+		err = rac.updateFirmwareLegacy(ctx, imagePath)
+	}
+	if err != nil {
+		return FirmwareVersion{}, err
+	}
+
+	return rac.waitForFirmwareReset(ctx, oldVersion)
+}
+
+// updateFirmwareLegacy runs fwupdate and polls its status until the update
+// completes or the idrac goes away to reset
+func (rac *idrac) updateFirmwareLegacy(ctx context.Context, imagePath string) error {
+	_, err := rac.fwupdate(ctx, []string{"-p", "-u", "-d", imagePath})
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(firmwareUpdatePoll)
+	defer ticker.Stop()
+
+	lastPercent := -1
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("firmware update did not complete (%w)", ctx.Err())
+		case <-ticker.C:
+		}
+
+		status, err := rac.FirmwareUpdateStatus(ctx)
+		if isTransportError(err) {
+			// the idrac stops answering once it resets
+			log.Printf("firmware update: status unavailable, idrac may be resetting (%s)", err)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("firmware update status failed (%w)", err)
+		}
+
+		if status.PercentComplete != lastPercent {
+			log.Printf("firmware update: %s", status.Message)
+			lastPercent = status.PercentComplete
+		}
+
+		if status.Failed {
+			return fmt.Errorf("firmware update failed (%s)", status.Message)
+		}
+		if status.Completed {
+			return nil
+		}
+	}
+}
+
+// updateFirmwareJob runs update and polls the resulting job until it
+// completes or the idrac goes away to reset
+func (rac *idrac) updateFirmwareJob(ctx context.Context, imagePath string) error {
+	execResp, err := rac.update(ctx, []string{"-f", imagePath})
+	if err != nil {
+		return err
+	}
+
+	jobIDs := parseJobIDs(execResp.Response.CommandOutput)
+	if len(jobIDs) == 0 {
+		return fmt.Errorf("firmware update did not return a job id (%s)", strings.TrimSpace(execResp.Response.CommandOutput))
+	}
+	jobID := jobIDs[0]
+	log.Printf("firmware update: job %s created", jobID)
+
+	ticker := time.NewTicker(firmwareUpdatePoll)
+	defer ticker.Stop()
+
+	lastPercent := -1
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("firmware update job %s did not complete (%w)", jobID, ctx.Err())
+		case <-ticker.C:
+		}
+
+		job, err := rac.GetJob(ctx, jobID)
+		if isTransportError(err) {
+			// the idrac stops answering once it resets
+			log.Printf("firmware update: job status unavailable, idrac may be resetting (%s)", err)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("firmware update job %s status failed (%w)", jobID, err)
+		}
+
+		if job.PercentComplete != lastPercent {
+			log.Printf("firmware update: job %s: %s (%d%%)", job.ID, job.Status, job.PercentComplete)
+			lastPercent = job.PercentComplete
+		}
+
+		if job.Done() {
+			if !job.Succeeded() {
+				return fmt.Errorf("firmware update job %s %s (%s)", job.ID, strings.ToLower(job.Status), job.Message)
+			}
+			return nil
+		}
+		if strings.EqualFold(job.Status, "Scheduled") {
+			return fmt.Errorf("firmware update job %s is scheduled and needs a server reboot to run (not an idrac image?)", job.ID)
+		}
+	}
+}
+
+// waitForFirmwareReset waits for the idrac to go away (reset) and come back
+// after a firmware update, then logs in again and returns the new firmware
+// version. Only transport errors count as the idrac going away; other
+// errors (e.g. the idrac being briefly busy) are polled through.
+func (rac *idrac) waitForFirmwareReset(ctx context.Context, oldVersion FirmwareVersion) (FirmwareVersion, error) {
+	ticker := time.NewTicker(firmwareUpdatePoll)
+	defer ticker.Stop()
+
+	// wait for the reset to start (the idrac stops answering)
+	log.Println("firmware update: waiting for the idrac to reset")
+	resetDeadline := time.Now().Add(firmwareResetStartWait)
+	for {
+		_, err := rac.PowerStatus(ctx)
+		if err != nil && ctx.Err() == nil && isTransportError(err) {
+			break
+		}
+		if time.Now().After(resetDeadline) {
+			return FirmwareVersion{}, errors.New("idrac did not reset after the firmware update")
+		}
+
+		select {
+		case <-ctx.Done():
+			return FirmwareVersion{}, fmt.Errorf("idrac did not reset after the firmware update (%w)", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+
+	// wait for it to come back (the version is then looked up again)
+	log.Println("firmware update: waiting for the idrac to return")
+	var lastErr error
+	for {
+		select {
+		case <-ctx.Done():
+			return FirmwareVersion{}, fmt.Errorf("idrac did not return after the firmware update (%w) (last error: %s)", ctx.Err(), lastErr)
+		case <-ticker.C:
+		}
+
+		rac.firmwareVersion = FirmwareVersion{}
+		_, lastErr = rac.Login()
+		if lastErr == nil {
+			break
+		}
+	}
+
+	newVersion, err := rac.FirmwareVersion(ctx)
+	if err != nil {
+		return FirmwareVersion{}, fmt.Errorf("idrac returned but its firmware version could not be read (%w)", err)
+	}
+
+	if oldVersion.Raw != "" && newVersion.Compare(oldVersion) == 0 {
+		return FirmwareVersion{}, fmt.Errorf("idrac returned but its firmware version is unchanged (%s), the update did not apply", newVersion)
+	}
+	log.Printf("firmware update: idrac returned with firmware %s", newVersion)
+
+	return newVersion, nil
+}
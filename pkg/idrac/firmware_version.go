@@ -0,0 +1,76 @@
+package idrac
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FirmwareVersion is a parsed firmware version. Dell versions have up to
+// four dot separated numbers (e.g. 2.52.52.52 or 7.00.00.00); missing parts
+// are 0. Raw is the version as reported.
+type FirmwareVersion struct {
+	Major int    `json:"major"`
+	Minor int    `json:"minor"`
+	Patch int    `json:"patch"`
+	Build int    `json:"build"`
+	Raw   string `json:"raw"`
+}
+
+// ParseFirmwareVersion parses a version string such as 2.52.52.52, ignoring
+// any trailing build info (e.g. "2.52.52.52 (Build 09)")
+func ParseFirmwareVersion(s string) (FirmwareVersion, error) {
+	raw := strings.TrimSpace(s)
+	fields := strings.Fields(raw)
+	if len(fields) == 0 {
+		return FirmwareVersion{}, fmt.Errorf("invalid firmware version (%s)", s)
+	}
+
+	parts := strings.Split(fields[0], ".")
+	if len(parts) > 4 {
+		return FirmwareVersion{}, fmt.Errorf("invalid firmware version (%s)", s)
+	}
+
+	nums := [4]int{}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return FirmwareVersion{}, fmt.Errorf("invalid firmware version (%s)", s)
+		}
+		nums[i] = n
+	}
+
+	return FirmwareVersion{
+		Major: nums[0],
+		Minor: nums[1],
+		Patch: nums[2],
+		Build: nums[3],
+		Raw:   raw,
+	}, nil
+}
+
+// String returns the version in major.minor.patch.build form
+func (v FirmwareVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d.%d", v.Major, v.Minor, v.Patch, v.Build)
+}
+
+// Compare returns -1, 0 or 1 if v is older than, the same as, or newer
+// than other
+func (v FirmwareVersion) Compare(other FirmwareVersion) int {
+	a := [4]int{v.Major, v.Minor, v.Patch, v.Build}
+	b := [4]int{other.Major, other.Minor, other.Patch, other.Build}
+	for i := range a {
+		if a[i] < b[i] {
+			return -1
+		} else if a[i] > b[i] {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// AtLeast returns true if v is the same as or newer than other
+func (v FirmwareVersion) AtLeast(other FirmwareVersion) bool {
+	return v.Compare(other) >= 0
+}
@@ -0,0 +1,152 @@
+package idrac
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// destructiveCommands are the subcommands that erase configuration or data
+// and can leave a remote idrac unreachable, with what each one does. They
+// are refused by Exec and must be run with ExecDestructive. systemerase
+// isn't implemented yet but is listed so it is guarded once it is.
+var destructiveCommands = map[string]string{
+	"clrsel":      "clears the System Event Log",
+	"racresetcfg": "resets the idrac to factory defaults, including its network settings and users",
+	"sslresetcfg": "replaces the web server certificate with a new self-signed certificate",
+	"systemerase": "erases server components (e.g. storage, BIOS, Lifecycle Controller data)",
+}
+
+var (
+	// ErrConfirmationRequired is wrapped by the error returned when a
+	// destructive subcommand is run without a valid confirmation
+	ErrConfirmationRequired = errors.New("confirmation required")
+
+	// ErrSnapshotFailed is wrapped by the error returned when the snapshot
+	// taken before a destructive subcommand fails (the subcommand isn't
+	// run, and anything written to the snapshot is incomplete)
+	ErrSnapshotFailed = errors.New("snapshot failed")
+)
+
+// IsDestructive returns true if command is a destructive subcommand
+func IsDestructive(command string) bool {
+	_, ok := destructiveCommands[command]
+	return ok
+}
+
+// DestructiveConfirmation is the confirmation needed to run a destructive
+// subcommand
+type DestructiveConfirmation struct {
+	// Token must be the idrac's ConfirmationToken for the command
+	Token string
+	// Hostname must echo back the name the idrac reports for itself (its
+	// DNS RAC name, with or without its DNS domain)
+	Hostname string
+	// Snapshot receives a backup (see Backup and WriteBackup) taken before
+	// the command runs. It is required; if the backup can't be taken the
+	// command doesn't run.
+	Snapshot io.Writer
+}
+
+// ConfirmationToken returns the token that confirms running command on this
+// idrac. It is derived from the hostname, command and current UTC date, so
+// a token only works for one host and command and stops working at the end
+// of the day (a saved script can't reset the wrong idrac by accident).
+//
+// The token is a speed bump, not a confirmation: it isn't secret or held by
+// the idrac, so anyone (or any script) can compute it. It only makes the
+// caller stop and copy it; the echoed hostname and the snapshot are what
+// guard against running the command on the wrong idrac.
+func (rac *idrac) ConfirmationToken(command string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(hostOnly(rac.hostname)) + "\x00" + command + "\x00" + time.Now().UTC().Format("2006-01-02")))
+	return strings.ToUpper(command) + "-" + hex.EncodeToString(sum[:])[:8]
+}
+
+// hostOnly returns hostname without a port
+func hostOnly(hostname string) string {
+	host, _, err := net.SplitHostPort(hostname)
+	if err != nil {
+		return hostname
+	}
+	return host
+}
+
+// errConfirmationRequired returns the error for an unconfirmed command,
+// explaining what it does and how to confirm it
+func (rac *idrac) errConfirmationRequired(command string) error {
+	return fmt.Errorf("%w: %s %s on %s; to run it, confirm with token %s and the idrac's hostname",
+		ErrConfirmationRequired, command, destructiveCommands[command], rac.hostname, rac.ConfirmationToken(command))
+}
+
+// reportedNames returns the names the idrac reports for itself: its DNS
+// RAC name and, if it has a DNS domain, its fully qualified name
+func (rac *idrac) reportedNames(ctx context.Context) ([]string, error) {
+	info, err := rac.GetSysInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(info.RAC.DNSRACName)
+	if name == "" {
+		return nil, errors.New("the idrac did not report a dns rac name")
+	}
+
+	names := []string{name}
+	if domain := strings.Trim(strings.TrimSpace(info.RAC.DNSDomain), "."); domain != "" {
+		names = append(names, name+"."+domain)
+	}
+
+	return names, nil
+}
+
+// ExecDestructive executes a destructive subcommand (see IsDestructive)
+// once it is confirmed: the token must match, the echoed hostname must match
+// the name the idrac reports for itself (so a stale address or DNS record
+// pointing at a different idrac is caught), and a configuration snapshot is
+// written to confirm.Snapshot before the command runs.
+func (rac *idrac) ExecDestructive(ctx context.Context, command string, flags []string, confirm DestructiveConfirmation) (execResp execResponse, err error) {
+	if !IsDestructive(command) {
+		return execResponse{}, fmt.Errorf("%s is not a destructive subcommand, use Exec", command)
+	}
+
+	// confirmation
+	if confirm.Token != rac.ConfirmationToken(command) {
+		return execResponse{}, rac.errConfirmationRequired(command)
+	}
+	names, err := rac.reportedNames(ctx)
+	if err != nil {
+		return execResponse{}, fmt.Errorf("%w: unable to read the idrac's name to check the echoed hostname (%w)", ErrConfirmationRequired, err)
+	}
+	echoed := strings.TrimSuffix(strings.TrimSpace(confirm.Hostname), ".")
+	matched := false
+	for _, name := range names {
+		if strings.EqualFold(echoed, name) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return execResponse{}, fmt.Errorf("%w: echoed hostname %q does not match the name %s reports (%s)", ErrConfirmationRequired, confirm.Hostname, rac.hostname, strings.Join(names, " or "))
+	}
+	if confirm.Snapshot == nil {
+		return execResponse{}, fmt.Errorf("%w: a snapshot destination is required", ErrConfirmationRequired)
+	}
+
+	// snapshot
+	backup, err := rac.Backup(ctx)
+	if err != nil {
+		return execResponse{}, fmt.Errorf("pre-%s %w, not running %s (%w)", command, ErrSnapshotFailed, command, err)
+	}
+	err = WriteBackup(confirm.Snapshot, backup)
+	if err != nil {
+		return execResponse{}, fmt.Errorf("pre-%s %w, not running %s (%w)", command, ErrSnapshotFailed, command, err)
+	}
+
+	return rac.exec(ctx, command, flags)
+}
@@ -12,6 +12,26 @@ type idrac struct {
 	username string
 	password string
 	client   *idracClient
+
+	// strictCerts is saved so new clients (e.g. to verify credentials) can
+	// be made the same way
+	strictCerts bool
+
+	// supportsAttributes caches whether the idrac supports get/set (0 not
+	// yet checked, 1 yes, -1 no)
+	supportsAttributes int
+
+	// firmwareVersion is populated by Discover or when first needed (see
+	// FirmwareVersion)
+	firmwareVersion FirmwareVersion
+
+	// closeStaleSessions makes a refused Login close the user's sessions from
+	// this host and retry
+	closeStaleSessions bool
+
+	// storageCommand caches the subcommand that reports storage (storage on
+	// iDRAC8/9, raid on iDRAC7), empty if not yet checked
+	storageCommand string
 }
 
 // NewIdrac creates an Idrac and client to access it
@@ -31,10 +51,11 @@ func NewIdrac(hostname, username, password string, strictCerts bool) (*idrac, er
 	}
 
 	return &idrac{
-		hostname: hostname,
-		username: username,
-		password: password,
-		client:   idracClient,
+		hostname:    hostname,
+		username:    username,
+		password:    password,
+		client:      idracClient,
+		strictCerts: strictCerts,
 	}, nil
 }
 
@@ -1,6 +1,7 @@
 package idrac
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/cookiejar"
@@ -12,6 +13,10 @@ import (
 type idracClient struct {
 	http      http.Client
 	userAgent string
+
+	// transfer is http, but with a timeout meant for large uploads and
+	// downloads (e.g. firmware images), which http's would cut off
+	transfer http.Client
 }
 
 // New creates a new http client using the custom struct. It also
@@ -19,6 +24,7 @@ type idracClient struct {
 func newIdracClient(strictCerts bool) (client *idracClient, err error) {
 	// make timeouts
 	clientTimeout := 60 * time.Second
+	transferTimeout := 60 * time.Minute
 
 	// make transport based on strictCerts
 	transport, err := newIdracAiaTransport(strictCerts)
@@ -38,6 +44,11 @@ func newIdracClient(strictCerts bool) (client *idracClient, err error) {
 	}
 	client.http.Jar = jar
 
+	// transfer client shares the transport and login cookie
+	client.transfer.Timeout = transferTimeout
+	client.transfer.Transport = transport
+	client.transfer.Jar = jar
+
 	// based on racadm 9.1.2
 	client.userAgent = "SSLClient"
 
@@ -45,8 +56,8 @@ func newIdracClient(strictCerts bool) (client *idracClient, err error) {
 }
 
 // newRequest creates an http request for the client to later do
-func (client *idracClient) newRequest(method string, url string, body io.Reader) (*http.Request, error) {
-	request, err := http.NewRequest(method, url, body)
+func (client *idracClient) newRequest(ctx context.Context, method string, url string, body io.Reader) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
@@ -70,9 +81,20 @@ func (client *idracClient) do(request *http.Request) (*http.Response, error) {
 	return response, nil
 }
 
+// doTransfer does the specified request with the transfer client, for
+// requests that move large amounts of data
+func (client *idracClient) doTransfer(request *http.Request) (*http.Response, error) {
+	return client.transfer.Do(request)
+}
+
 // Get does a get request to the specified url
 func (client *idracClient) Get(url string) (*http.Response, error) {
-	request, err := client.newRequest(http.MethodGet, url, nil)
+	return client.GetContext(context.Background(), url)
+}
+
+// GetContext does a get request to the specified url, bound to ctx
+func (client *idracClient) GetContext(ctx context.Context, url string) (*http.Response, error) {
+	request, err := client.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +116,13 @@ func (client *idracClient) Get(url string) (*http.Response, error) {
 // Post does a post request using the specified url, content type, and
 // body
 func (client *idracClient) Post(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	request, err := client.newRequest("POST", url, body)
+	return client.PostContext(context.Background(), url, contentType, body)
+}
+
+// PostContext does a post request using the specified url, content type, and
+// body, bound to ctx
+func (client *idracClient) PostContext(ctx context.Context, url string, contentType string, body io.Reader) (resp *http.Response, err error) {
+	request, err := client.newRequest(ctx, "POST", url, body)
 	if err != nil {
 		return nil, err
 	}
@@ -103,3 +131,16 @@ func (client *idracClient) Post(url string, contentType string, body io.Reader)
 
 	return client.do(request)
 }
+
+// PostTransferContext is PostContext, using the transfer client (for large
+// bodies, e.g. firmware images)
+func (client *idracClient) PostTransferContext(ctx context.Context, url string, contentType string, body io.Reader) (resp *http.Response, err error) {
+	request, err := client.newRequest(ctx, "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", contentType)
+
+	return client.doTransfer(request)
+}
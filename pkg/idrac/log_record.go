@@ -0,0 +1,202 @@
+package idrac
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogRecord is a single RAC log or Lifecycle log record. The RAC log on
+// iDRAC7/8 uses Record/Date/Time/Source/Description, while the Lifecycle
+// log (and the RAC log on iDRAC9) uses SeqNumber/Message ID/Category/...
+type LogRecord struct {
+	Sequence    int       `json:"sequence,omitempty"`
+	Record      int       `json:"record,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+	DateTime    string    `json:"date_time"`
+	MessageID   string    `json:"message_id,omitempty"`
+	Category    string    `json:"category,omitempty"`
+	Agent       string    `json:"agent,omitempty"`
+	Severity    string    `json:"severity,omitempty"`
+	Source      string    `json:"source,omitempty"`
+	Message     string    `json:"message"`
+	MessageArgs []string  `json:"message_args,omitempty"`
+	FQDD        string    `json:"fqdd,omitempty"`
+}
+
+// logTimeLayouts are the Date/Time and Timestamp formats used by the various
+// idrac versions
+var logTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"Jan 2 2006 15:04:05",
+	"Jan _2 2006 15:04:05",
+	"Mon Jan 2 2006 15:04:05",
+	"01/02/2006 15:04:05",
+}
+
+// parseLogTime parses a log timestamp, returning the zero time if the format
+// isn't known
+func parseLogTime(value string) time.Time {
+	for _, layout := range logTimeLayouts {
+		t, err := time.ParseInLocation(layout, value, time.Local)
+		if err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// ParseLogRecords parses getraclog or lclog view output into records. Lines
+// are either "Key: Value" or "Key = Value" and records are separated by
+// dashed lines.
+func ParseLogRecords(output string) []LogRecord {
+	records := []LogRecord{}
+
+	var record *LogRecord
+	newRecord := func() {
+		records = append(records, LogRecord{})
+		record = &records[len(records)-1]
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// separator ends the current record
+		if line == "" || strings.Trim(line, "-") == "" {
+			record = nil
+			continue
+		}
+
+		// split on whichever separator comes first
+		sep := ":"
+		if eq := strings.Index(line, "="); eq >= 0 {
+			if colon := strings.Index(line, ":"); colon < 0 || eq < colon {
+				sep = "="
+			}
+		}
+		key, value, found := strings.Cut(line, sep)
+		if !found {
+			// continuation of a multi line message
+			if record != nil {
+				record.Message += " " + line
+			}
+			continue
+		}
+		key = strings.ToLower(strings.Join(strings.Fields(key), " "))
+		value = strings.TrimSpace(value)
+
+		// record start keys
+		switch key {
+		case "record":
+			newRecord()
+			record.Record, _ = strconv.Atoi(value)
+			continue
+		case "seqnumber":
+			newRecord()
+			record.Sequence, _ = strconv.Atoi(value)
+			continue
+		}
+
+		if record == nil {
+			newRecord()
+		}
+
+		switch {
+		case key == "date/time" || key == "timestamp":
+			record.DateTime = value
+			record.Timestamp = parseLogTime(value)
+		case key == "message id":
+			record.MessageID = value
+		case key == "category":
+			record.Category = value
+		case key == "agentid":
+			record.Agent = value
+		case key == "severity":
+			record.Severity = value
+		case key == "source":
+			record.Source = value
+		case key == "message" || key == "description":
+			record.Message = value
+		case strings.HasPrefix(key, "message arg"):
+			record.MessageArgs = append(record.MessageArgs, value)
+		case key == "fqdd":
+			record.FQDD = value
+		}
+	}
+
+	return records
+}
+
+// LogCursor tracks the newest log record seen so a collector can poll for
+// only new records. Records with a sequence number are compared by sequence,
+// otherwise (RAC log on iDRAC7/8) by timestamp. Timestamps only have second
+// precision, so the records seen at the cursor's timestamp are also kept to
+// tell them from new records written in the same second.
+type LogCursor struct {
+	Sequence  int       `json:"sequence"`
+	Timestamp time.Time `json:"timestamp"`
+	// SeenAtTimestamp identifies (see recordID) the records already seen at
+	// Timestamp
+	SeenAtTimestamp []string `json:"seen_at_timestamp,omitempty"`
+}
+
+// recordID identifies a record without a sequence number by its content
+func (record LogRecord) recordID() string {
+	return strings.Join([]string{record.DateTime, record.Source, record.MessageID, record.Message}, "\x00")
+}
+
+// isNew returns true if record is newer than the cursor
+func (cursor LogCursor) isNew(record LogRecord) bool {
+	if record.Sequence != 0 {
+		return record.Sequence > cursor.Sequence
+	}
+
+	if record.Timestamp.Equal(cursor.Timestamp) {
+		id := record.recordID()
+		for _, seen := range cursor.SeenAtTimestamp {
+			if seen == id {
+				return false
+			}
+		}
+		return true
+	}
+
+	return record.Timestamp.After(cursor.Timestamp)
+}
+
+// Advance returns the cursor moved past every record in records
+func (cursor LogCursor) Advance(records []LogRecord) LogCursor {
+	for _, record := range records {
+		if record.Sequence != 0 {
+			if record.Sequence > cursor.Sequence {
+				cursor.Sequence = record.Sequence
+			}
+			continue
+		}
+
+		switch {
+		case record.Timestamp.After(cursor.Timestamp):
+			cursor.Timestamp = record.Timestamp
+			cursor.SeenAtTimestamp = []string{record.recordID()}
+		case record.Timestamp.Equal(cursor.Timestamp):
+			cursor.SeenAtTimestamp = append(cursor.SeenAtTimestamp, record.recordID())
+		}
+	}
+
+	return cursor
+}
+
+// newRecords returns the records in records that are newer than cursor
+func (cursor LogCursor) newRecords(records []LogRecord) []LogRecord {
+	newer := []LogRecord{}
+	for _, record := range records {
+		if cursor.isNew(record) {
+			newer = append(newer, record)
+		}
+	}
+
+	return newer
+}
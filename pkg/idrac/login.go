@@ -2,10 +2,14 @@ package idrac
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
+	"errors"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 const endpointLogin = "/cgi-bin/login"
@@ -30,10 +34,77 @@ type LoginResponse struct {
 		StateName         string     `xml:"STATENAME"`
 		DefaultCredential string     `xml:"DEFCRED"`
 	}
+
+	// typed versions of the above, populated by Login
+	DefaultCredentialsInUse bool       `xml:"-"`
+	State                   LoginState `xml:"-"`
 }
 
-// login logs into the idrac and saves the login cookie (`sid`)
+// LoginState is the login state the idrac reports (STATENAME, or STATE if
+// there is no name)
+type LoginState string
+
+// known login states
+const (
+	LoginStateUnknown = LoginState("")
+	LoginStateOK      = LoginState("OK")
+)
+
+// parseLoginState returns the LoginState for the STATE and STATENAME values
+func parseLoginState(code, name string) LoginState {
+	name = strings.TrimSpace(name)
+	code = strings.TrimSpace(code)
+	switch {
+	case strings.EqualFold(name, string(LoginStateOK)) || (name == "" && code == "0"):
+		return LoginStateOK
+	case name != "":
+		return LoginState(name)
+	}
+
+	return LoginState(code)
+}
+
+// parseDefaultCredential returns true if the DEFCRED value indicates the
+// default credentials (e.g. root/calvin) are in use
+func parseDefaultCredential(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return value == "1" || value == "true" || value == "yes"
+}
+
+// Login logs into the idrac and saves the login cookie (`sid`). If closing
+// stale sessions is enabled (see SetCloseStaleSessions) and the login is
+// refused for a reason other than bad credentials (e.g. the session limit),
+// this user's sessions from this host are closed and the login is retried.
 func (rac *idrac) Login() (loginResp LoginResponse, err error) {
+	loginResp, err = rac.login()
+	if err == nil || !rac.closeStaleSessions || !mayBeSessionLimit(err) {
+		return loginResp, err
+	}
+
+	closed, closeErr := rac.closeOwnSessions(context.Background())
+	if closeErr != nil {
+		log.Printf("login refused (%s), unable to close stale sessions (%s)", err, closeErr)
+		return LoginResponse{}, err
+	}
+	log.Printf("login refused (%s), closed %d stale session(s) of this user from this host, retrying", err, closed)
+
+	return rac.login()
+}
+
+// mayBeSessionLimit returns true if the login error could be the session
+// limit. The return code for it isn't the same on every idrac generation,
+// so any refusal other than invalid credentials counts.
+func mayBeSessionLimit(err error) bool {
+	rc := ReturnCode("")
+	if !errors.As(err, &rc) {
+		return false
+	}
+
+	return rc != RcIdrac6InvalidUserPassword && rc != RcIdrac7InvalidUserPassword
+}
+
+// login does a single login attempt and saves the login cookie
+func (rac *idrac) login() (loginResp LoginResponse, err error) {
 	// make login payload and marshal it
 	payload := loginPayload{}
 	payload.Request.Username = rac.username
@@ -67,6 +138,10 @@ func (rac *idrac) Login() (loginResp LoginResponse, err error) {
 		return LoginResponse{}, loginResp.Response.ReturnCode
 	}
 
+	// typed fields
+	loginResp.DefaultCredentialsInUse = parseDefaultCredential(loginResp.Response.DefaultCredential)
+	loginResp.State = parseLoginState(loginResp.Response.State, loginResp.Response.StateName)
+
 	// save login cookie to jar
 	url, err := url.Parse("https://" + rac.hostname)
 	if err != nil {
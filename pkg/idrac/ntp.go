@@ -0,0 +1,100 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ntpServerCount is the number of NTP servers an idrac can be configured with
+const ntpServerCount = 3
+
+// NTPConfig is the idrac's NTP configuration
+type NTPConfig struct {
+	Enabled bool     `json:"enabled"`
+	Servers []string `json:"servers"`
+}
+
+// NTPConfig returns the idrac's NTP configuration, read from
+// iDRAC.NTPConfigGroup (iDRAC8/9) or cfgRemoteHosts (iDRAC6/7)
+func (rac *idrac) NTPConfig(ctx context.Context) (NTPConfig, error) {
+	var tree AttributeTree
+	var err error
+	enableName := ""
+	serverName := ""
+
+	if rac.usesAttributes(ctx) {
+		tree, err = rac.Get(ctx, "iDRAC.NTPConfigGroup")
+		enableName, serverName = "NTPEnable", "NTP%d"
+	} else {
+		tree, err = rac.GetConfig(ctx, "cfgRemoteHosts", 0)
+		enableName, serverName = "cfgRhostsNtpEnable", "cfgRhostsNtpServer%d"
+	}
+	if err != nil {
+		return NTPConfig{}, err
+	}
+
+	cfg := NTPConfig{}
+	enable, _ := tree.Attribute(enableName)
+	cfg.Enabled = enable.Value == "1" || strings.EqualFold(enable.Value, "enabled")
+	for i := 1; i <= ntpServerCount; i++ {
+		server, _ := tree.Attribute(fmt.Sprintf(serverName, i))
+		if server.Value != "" {
+			cfg.Servers = append(cfg.Servers, server.Value)
+		}
+	}
+
+	return cfg, nil
+}
+
+// SetNTPConfig sets up to three NTP servers (unused slots are cleared) and
+// enables or disables NTP
+func (rac *idrac) SetNTPConfig(ctx context.Context, cfg NTPConfig) error {
+	if len(cfg.Servers) > ntpServerCount {
+		return fmt.Errorf("at most %d ntp servers can be configured", ntpServerCount)
+	}
+	if cfg.Enabled && len(cfg.Servers) == 0 {
+		return errors.New("at least one ntp server is needed to enable ntp")
+	}
+
+	attributes := rac.usesAttributes(ctx)
+
+	// servers first, so ntp is never enabled without them
+	for i := 1; i <= ntpServerCount; i++ {
+		server := ""
+		if i <= len(cfg.Servers) {
+			server = cfg.Servers[i-1]
+		}
+
+		var err error
+		if attributes {
+			_, err = rac.Set(ctx, fmt.Sprintf("iDRAC.NTPConfigGroup.NTP%d", i), server)
+		} else {
+			err = rac.Config(ctx, "cfgRemoteHosts", fmt.Sprintf("cfgRhostsNtpServer%d", i), 0, server)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to set ntp server %d (%w)", i, err)
+		}
+	}
+
+	var err error
+	if attributes {
+		value := "Disabled"
+		if cfg.Enabled {
+			value = "Enabled"
+		}
+		_, err = rac.Set(ctx, "iDRAC.NTPConfigGroup.NTPEnable", value)
+	} else {
+		value := "0"
+		if cfg.Enabled {
+			value = "1"
+		}
+		err = rac.Config(ctx, "cfgRemoteHosts", "cfgRhostsNtpEnable", 0, value)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set ntp enable (%w)", err)
+	}
+
+	return nil
+}
@@ -2,9 +2,11 @@ package idrac
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"io"
+	"math"
 	"net/http"
 	"strconv"
 )
@@ -17,6 +19,7 @@ type putfilePayload struct {
 	filename string // file name on idrac
 	flags    int    // file flags (unsure of purpose)
 	content  []byte // the actual file content being put
+	binary   bool   // if true, content is sent as is (no new line normalization)
 }
 
 // Bytes() translates the putfilePayload into the byte slice
@@ -30,11 +33,14 @@ func (payload *putfilePayload) bytes() []byte {
 	name := []byte(payload.filename)
 
 	// normalize new line style in file content (racadm doesn't do
-	// this but doing for consistency)
-	// windows
-	payload.content = bytes.Replace(payload.content, []byte{13, 10}, []byte{10}, -1)
-	// mac
-	payload.content = bytes.Replace(payload.content, []byte{13}, []byte{10}, -1)
+	// this but doing for consistency); never for binary content (e.g.
+	// firmware images) which this would corrupt
+	if !payload.binary {
+		// windows
+		payload.content = bytes.Replace(payload.content, []byte{13, 10}, []byte{10}, -1)
+		// mac
+		payload.content = bytes.Replace(payload.content, []byte{13}, []byte{10}, -1)
+	}
 
 	// calc file content len and encode to little endian
 	fileContentLen := len(payload.content)
@@ -63,11 +69,23 @@ func (payload *putfilePayload) bytes() []byte {
 // putfile sends the specified data as an octetstream to the rac's
 // putfile endpoint
 func (rac *idrac) putfile(payload putfilePayload) (err error) {
+	return rac.putfileContext(context.Background(), payload)
+}
+
+// putfileContext is putfile, bound to ctx
+func (rac *idrac) putfileContext(ctx context.Context, payload putfilePayload) (err error) {
+	// the length is encoded as 4 bytes
+	if uint64(len(payload.content)) > math.MaxUint32 {
+		return errors.New("error: file is too large to put")
+	}
+
 	// post
 	payloadBytes := payload.bytes()
 	// log.Println(string(payloadBytes))
 
-	resp, err := rac.client.Post(rac.url()+endpointPutfile, "application/octet-stream", bytes.NewBuffer(payloadBytes))
+	// files can be large (e.g. firmware images), so use the transfer client
+	// (ctx still bounds the request)
+	resp, err := rac.client.PostTransferContext(ctx, rac.url()+endpointPutfile, "application/octet-stream", bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,368 @@
+package idrac
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SCPFormat is the file format of a Server Configuration Profile
+type SCPFormat string
+
+const (
+	SCPFormatXML  = SCPFormat("xml")
+	SCPFormatJSON = SCPFormat("json")
+)
+
+// SCPAttribute is one attribute of a Server Configuration Profile component.
+// Commented attributes are those the idrac exports inside xml comments, or
+// with "Set On Import": "False" in json (read-only, or not applicable in the
+// current configuration); they are kept so a profile round trips, but are
+// not applied on import. Comment is the json Comment (the reason), if any.
+type SCPAttribute struct {
+	Name      string `json:"Name"`
+	Value     string `json:"Value"`
+	Commented bool   `json:"-"`
+	Comment   string `json:"Comment,omitempty"`
+}
+
+// scpJSONAttribute is an SCPAttribute as it appears in a json profile
+type scpJSONAttribute struct {
+	Name        string `json:"Name"`
+	Value       string `json:"Value"`
+	SetOnImport string `json:"Set On Import,omitempty"`
+	Comment     string `json:"Comment,omitempty"`
+}
+
+// UnmarshalJSON decodes a json profile attribute, marking it Commented if it
+// isn't set on import
+func (attr *SCPAttribute) UnmarshalJSON(data []byte) error {
+	jsonAttr := scpJSONAttribute{}
+	err := json.Unmarshal(data, &jsonAttr)
+	if err != nil {
+		return err
+	}
+
+	*attr = SCPAttribute{
+		Name:      jsonAttr.Name,
+		Value:     jsonAttr.Value,
+		Commented: strings.EqualFold(jsonAttr.SetOnImport, "False"),
+		Comment:   jsonAttr.Comment,
+	}
+	return nil
+}
+
+// MarshalJSON encodes a json profile attribute; a Commented attribute is
+// written with "Set On Import": "False" so the idrac doesn't apply it
+func (attr SCPAttribute) MarshalJSON() ([]byte, error) {
+	jsonAttr := scpJSONAttribute{
+		Name:    attr.Name,
+		Value:   attr.Value,
+		Comment: attr.Comment,
+	}
+	if attr.Commented {
+		jsonAttr.SetOnImport = "False"
+	}
+
+	return json.Marshal(jsonAttr)
+}
+
+// SCPComponent is a component (e.g. BIOS.Setup.1-1, iDRAC.Embedded.1 or
+// RAID.Integrated.1-1) of a Server Configuration Profile. Components can
+// contain components (e.g. a RAID controller's disks).
+type SCPComponent struct {
+	FQDD       string         `json:"FQDD"`
+	Attributes []SCPAttribute `json:"Attributes,omitempty"`
+	Components []SCPComponent `json:"Components,omitempty"`
+}
+
+// SystemConfiguration is a parsed Server Configuration Profile (the xml or
+// json exported by get -f -t xml|json)
+type SystemConfiguration struct {
+	Model      string         `json:"Model,omitempty"`
+	ServiceTag string         `json:"ServiceTag,omitempty"`
+	TimeStamp  string         `json:"TimeStamp,omitempty"`
+	Components []SCPComponent `json:"Components"`
+}
+
+// scpJSONDocument is the top level of a json profile
+type scpJSONDocument struct {
+	SystemConfiguration SystemConfiguration `json:"SystemConfiguration"`
+}
+
+// detectSCPFormat returns the format of profile data, based on its first
+// non-space character
+func detectSCPFormat(data []byte) (SCPFormat, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return SCPFormatXML, nil
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return SCPFormatJSON, nil
+	}
+
+	return "", errors.New("data is not an xml or json server configuration profile")
+}
+
+// ParseSCP parses a Server Configuration Profile in either format, returning
+// it along with the detected format
+func ParseSCP(data []byte) (SystemConfiguration, SCPFormat, error) {
+	format, err := detectSCPFormat(data)
+	if err != nil {
+		return SystemConfiguration{}, "", err
+	}
+
+	if format == SCPFormatJSON {
+		doc := scpJSONDocument{}
+		err = json.Unmarshal(data, &doc)
+		if err != nil {
+			return SystemConfiguration{}, "", fmt.Errorf("invalid json server configuration profile (%w)", err)
+		}
+		return doc.SystemConfiguration, format, nil
+	}
+
+	sc, err := parseSCPXML(data)
+	if err != nil {
+		return SystemConfiguration{}, "", fmt.Errorf("invalid xml server configuration profile (%w)", err)
+	}
+
+	return sc, format, nil
+}
+
+// parseSCPXML parses an xml profile. It walks the tokens (rather than
+// unmarshalling) so commented out attributes are kept.
+func parseSCPXML(data []byte) (SystemConfiguration, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			return SystemConfiguration{}, errors.New("no SystemConfiguration element")
+		}
+		if err != nil {
+			return SystemConfiguration{}, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local != "SystemConfiguration" {
+			return SystemConfiguration{}, fmt.Errorf("unexpected root element %s", start.Name.Local)
+		}
+
+		sc := SystemConfiguration{
+			Model:      xmlAttr(start, "Model"),
+			ServiceTag: xmlAttr(start, "ServiceTag"),
+			TimeStamp:  xmlAttr(start, "TimeStamp"),
+		}
+		comp, err := parseSCPXMLComponent(d)
+		if err != nil {
+			return SystemConfiguration{}, err
+		}
+		sc.Components = comp.Components
+
+		return sc, nil
+	}
+}
+
+// parseSCPXMLComponent reads the content of an element (whose start was
+// just read) up to and including its end
+func parseSCPXMLComponent(d *xml.Decoder) (SCPComponent, error) {
+	comp := SCPComponent{}
+
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return SCPComponent{}, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "Component":
+				child, err := parseSCPXMLComponent(d)
+				if err != nil {
+					return SCPComponent{}, err
+				}
+				child.FQDD = xmlAttr(t, "FQDD")
+				comp.Components = append(comp.Components, child)
+
+			case "Attribute":
+				value := ""
+				err = d.DecodeElement(&value, &t)
+				if err != nil {
+					return SCPComponent{}, err
+				}
+				comp.Attributes = append(comp.Attributes, SCPAttribute{
+					Name:  xmlAttr(t, "Name"),
+					Value: value,
+				})
+
+			default:
+				err = d.Skip()
+				if err != nil {
+					return SCPComponent{}, err
+				}
+			}
+
+		case xml.Comment:
+			comp.Attributes = append(comp.Attributes, parseSCPXMLComment(t)...)
+
+		case xml.EndElement:
+			return comp, nil
+		}
+	}
+}
+
+// parseSCPXMLComment returns the attributes in a comment, e.g.
+// <!-- <Attribute Name="SysMemSize">64 GB</Attribute> -->
+// Comments that aren't attributes are dropped.
+func parseSCPXMLComment(comment xml.Comment) []SCPAttribute {
+	attrs := []SCPAttribute{}
+
+	d := xml.NewDecoder(bytes.NewReader(comment))
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return attrs
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "Attribute" {
+			continue
+		}
+
+		value := ""
+		if d.DecodeElement(&value, &start) != nil {
+			return attrs
+		}
+		attrs = append(attrs, SCPAttribute{
+			Name:      xmlAttr(start, "Name"),
+			Value:     value,
+			Commented: true,
+		})
+	}
+}
+
+// xmlAttr returns the value of the named attribute of an element
+func xmlAttr(start xml.StartElement, name string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// Marshal encodes the profile in the specified format, in the layout the
+// idrac exports (and imports)
+func (sc SystemConfiguration) Marshal(format SCPFormat) ([]byte, error) {
+	switch format {
+	case SCPFormatJSON:
+		return json.MarshalIndent(scpJSONDocument{SystemConfiguration: sc}, "", "  ")
+
+	case SCPFormatXML:
+		buf := &bytes.Buffer{}
+		fmt.Fprintf(buf, "<SystemConfiguration Model=%s ServiceTag=%s TimeStamp=%s>\n",
+			xmlQuote(sc.Model), xmlQuote(sc.ServiceTag), xmlQuote(sc.TimeStamp))
+		for _, comp := range sc.Components {
+			writeSCPXMLComponent(buf, comp, "")
+		}
+		buf.WriteString("</SystemConfiguration>\n")
+		return buf.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("invalid server configuration profile format (%s)", format)
+}
+
+// writeSCPXMLComponent writes a component and its children to buf
+func writeSCPXMLComponent(buf *bytes.Buffer, comp SCPComponent, indent string) {
+	fmt.Fprintf(buf, "%s<Component FQDD=%s>\n", indent, xmlQuote(comp.FQDD))
+	for _, attr := range comp.Attributes {
+		element := fmt.Sprintf("<Attribute Name=%s>%s</Attribute>", xmlQuote(attr.Name), xmlEscape(attr.Value))
+		if attr.Commented {
+			element = "<!-- " + element + " -->"
+		}
+		fmt.Fprintf(buf, "%s  %s\n", indent, element)
+	}
+	for _, child := range comp.Components {
+		writeSCPXMLComponent(buf, child, indent+"  ")
+	}
+	fmt.Fprintf(buf, "%s</Component>\n", indent)
+}
+
+// xmlEscape escapes s for use as xml text
+func xmlEscape(s string) string {
+	buf := &bytes.Buffer{}
+	_ = xml.EscapeText(buf, []byte(s))
+	return buf.String()
+}
+
+// xmlQuote escapes and quotes s for use as an xml attribute value
+func xmlQuote(s string) string {
+	return "\"" + xmlEscape(s) + "\""
+}
+
+// scpComponentMatches returns true if fqdd is selected by filter. A filter
+// matches an exact FQDD (case insensitive) or, if it has no dot, the FQDD's
+// type (the part before the first dot), e.g. BIOS matches BIOS.Setup.1-1 and
+// NIC matches every NIC port.
+func scpComponentMatches(fqdd, filter string) bool {
+	if strings.EqualFold(fqdd, filter) {
+		return true
+	}
+	if strings.Contains(filter, ".") {
+		return false
+	}
+
+	fqddType, _, _ := strings.Cut(fqdd, ".")
+	return strings.EqualFold(fqddType, filter)
+}
+
+// FilterComponents returns a copy of the profile with only the components
+// selected by filters (see scpComponentMatches). A selected component keeps
+// all of its child components; a component that isn't selected is kept
+// (without its own attributes) only if one of its children is, so nested
+// components keep their parent. An empty filters keeps everything.
+func (sc SystemConfiguration) FilterComponents(filters []string) SystemConfiguration {
+	if len(filters) == 0 {
+		return sc
+	}
+
+	selected := func(fqdd string) bool {
+		for _, filter := range filters {
+			if scpComponentMatches(fqdd, strings.TrimSpace(filter)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var filter func(comps []SCPComponent) []SCPComponent
+	filter = func(comps []SCPComponent) []SCPComponent {
+		out := []SCPComponent{}
+		for _, comp := range comps {
+			if selected(comp.FQDD) {
+				out = append(out, comp)
+				continue
+			}
+
+			children := filter(comp.Components)
+			if len(children) > 0 {
+				out = append(out, SCPComponent{
+					FQDD:       comp.FQDD,
+					Components: children,
+				})
+			}
+		}
+		return out
+	}
+
+	sc.Components = filter(sc.Components)
+	return sc
+}
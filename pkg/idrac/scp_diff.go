@@ -0,0 +1,79 @@
+package idrac
+
+import (
+	"sort"
+	"strings"
+)
+
+// SCPChange is the kind of an SCPDifference
+type SCPChange string
+
+const (
+	SCPChangeAdded   = SCPChange("added")
+	SCPChangeRemoved = SCPChange("removed")
+	SCPChangeChanged = SCPChange("changed")
+)
+
+// SCPDifference is an attribute that differs between two profiles.
+// Component is the FQDD path (nested components are joined with /).
+type SCPDifference struct {
+	Component string    `json:"component"`
+	Attribute string    `json:"attribute"`
+	Change    SCPChange `json:"change"`
+	Old       string    `json:"old,omitempty"`
+	New       string    `json:"new,omitempty"`
+}
+
+// flattenSCP maps component path and attribute name to value, for every
+// attribute that would be applied (commented attributes are skipped)
+func flattenSCP(comps []SCPComponent, parent string, out map[[2]string]string) {
+	for _, comp := range comps {
+		path := comp.FQDD
+		if parent != "" {
+			path = parent + "/" + comp.FQDD
+		}
+
+		for _, attr := range comp.Attributes {
+			if !attr.Commented {
+				out[[2]string{path, attr.Name}] = attr.Value
+			}
+		}
+		flattenSCP(comp.Components, path, out)
+	}
+}
+
+// DiffSCP compares two profiles (e.g. of two servers that should be
+// identical) and returns the attributes that were added, removed or changed
+// from profile from to profile to, sorted by component and attribute. Commented
+// (read-only) attributes are ignored since they are never applied.
+func DiffSCP(from, to SystemConfiguration) []SCPDifference {
+	oldAttrs := make(map[[2]string]string)
+	newAttrs := make(map[[2]string]string)
+	flattenSCP(from.Components, "", oldAttrs)
+	flattenSCP(to.Components, "", newAttrs)
+
+	diffs := []SCPDifference{}
+	for key, oldValue := range oldAttrs {
+		newValue, ok := newAttrs[key]
+		switch {
+		case !ok:
+			diffs = append(diffs, SCPDifference{Component: key[0], Attribute: key[1], Change: SCPChangeRemoved, Old: oldValue})
+		case newValue != oldValue:
+			diffs = append(diffs, SCPDifference{Component: key[0], Attribute: key[1], Change: SCPChangeChanged, Old: oldValue, New: newValue})
+		}
+	}
+	for key, newValue := range newAttrs {
+		if _, ok := oldAttrs[key]; !ok {
+			diffs = append(diffs, SCPDifference{Component: key[0], Attribute: key[1], Change: SCPChangeAdded, New: newValue})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Component != diffs[j].Component {
+			return strings.ToLower(diffs[i].Component) < strings.ToLower(diffs[j].Component)
+		}
+		return diffs[i].Attribute < diffs[j].Attribute
+	})
+
+	return diffs
+}
@@ -0,0 +1,395 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// scpMaxOutputLen is the output length requested for a profile export, since
+// a profile is far larger than any other output
+const scpMaxOutputLen = "0xfffff"
+
+var errSCPNoContent = errors.New("the idrac did not return the server configuration profile (export to a network share with -l instead)")
+
+// validate checks the format is xml or json
+func (format SCPFormat) validate() error {
+	if format != SCPFormatXML && format != SCPFormatJSON {
+		return errors.New("server configuration profile format (-t) must be xml or json")
+	}
+	return nil
+}
+
+// SCPShutdownType is how the server is shut down to apply an imported profile
+type SCPShutdownType string
+
+const (
+	SCPShutdownGraceful = SCPShutdownType("Graceful")
+	SCPShutdownForced   = SCPShutdownType("Forced")
+	SCPShutdownNoReboot = SCPShutdownType("NoReboot")
+)
+
+// SCPImportOptions are the options for a profile import. Zero values use the
+// idrac's defaults (graceful shutdown, server left on).
+type SCPImportOptions struct {
+	ShutdownType SCPShutdownType
+	// WaitMinutes is how long to wait for a graceful shutdown
+	WaitMinutes int
+	// EndPowerOff leaves the server off after the import
+	EndPowerOff bool
+}
+
+// flags returns the racadm options for opts
+func (opts SCPImportOptions) flags() (string, error) {
+	cmdOpts := ""
+
+	switch opts.ShutdownType {
+	case "":
+	case SCPShutdownGraceful, SCPShutdownForced, SCPShutdownNoReboot:
+		cmdOpts += " -b " + string(opts.ShutdownType)
+	default:
+		return "", errors.New("shutdown type (-b) must be Graceful, Forced, or NoReboot")
+	}
+
+	if opts.WaitMinutes < 0 {
+		return "", errors.New("wait time (-w) must not be negative")
+	}
+	if opts.WaitMinutes > 0 {
+		cmdOpts += " -w " + strconv.Itoa(opts.WaitMinutes)
+	}
+
+	if opts.EndPowerOff {
+		cmdOpts += " -s Off"
+	}
+
+	return cmdOpts, nil
+}
+
+// scpRemoteFile is the name a profile is put on (or exported to) the idrac as
+func scpRemoteFile(format SCPFormat) string {
+	return "scpfile." + string(format)
+}
+
+// extractSCP returns the profile in get -f output (racadm messages may come
+// before it), or "" if there isn't one
+func extractSCP(output string, format SCPFormat) string {
+	if format == SCPFormatJSON {
+		start := strings.Index(output, "{")
+		end := strings.LastIndex(output, "}")
+		if start < 0 || end < start {
+			return ""
+		}
+		return output[start : end+1]
+	}
+
+	const endTag = "</SystemConfiguration>"
+	start := strings.Index(output, "<SystemConfiguration")
+	end := strings.LastIndex(output, endTag)
+	if start < 0 || end < start {
+		return ""
+	}
+	return output[start : end+len(endTag)]
+}
+
+// getSCP exports a profile. To a share, the output has the export job's id.
+// Otherwise, the profile is returned in the output and saved to file.
+func (rac *idrac) getSCP(ctx context.Context, file string, format SCPFormat, share NetworkShare) (execResp execResponse, err error) {
+	// validate
+	if file == "" {
+		return execResponse{}, errors.New("file name (-f) must be specified")
+	}
+	err = format.validate()
+	if err != nil {
+		return execResponse{}, err
+	}
+	err = share.validate()
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// to a share, the file name is the name on the share
+	remoteFile := scpRemoteFile(format)
+	if share.Path != "" {
+		if strings.ContainsAny(file, " \t\"/\\") {
+			return execResponse{}, errors.New("file name (-f) must not contain spaces, quotes, or slashes when exporting to a share")
+		}
+		remoteFile = file
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = fmt.Sprintf("racadm get -f %s -t %s%s", remoteFile, format, share.flags())
+	payload.Request.MaxOutputLen = scpMaxOutputLen
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload; a local export returns the whole profile, which
+	// isn't logged
+	execResp, err = rac.executePayloadContext(withQuietOutput(ctx), payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// share export is done (as a job)
+	if share.Path != "" {
+		return execResp, nil
+	}
+
+	// confirm the whole profile came back, then save it
+	profile := extractSCP(execResp.Response.CommandOutput, format)
+	if profile == "" {
+		return execResponse{}, errSCPNoContent
+	}
+	_, _, err = ParseSCP([]byte(profile))
+	if err != nil {
+		return execResponse{}, fmt.Errorf("incomplete server configuration profile (%w)", err)
+	}
+
+	err = os.WriteFile(file, []byte(profile+"\n"), 0600)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// setSCPFlags parses set's profile import flags and imports the profile. Bad
+// flags return an error (rather than exiting) since this is reached from
+// Exec by library callers.
+func (rac *idrac) setSCPFlags(ctx context.Context, flags []string) (execResp execResponse, err error) {
+	// parse command flags (options)
+	file := ""
+	format := ""
+	shutdownType := ""
+	endPowerState := ""
+	opts := SCPImportOptions{}
+	share := NetworkShare{}
+
+	fs := flag.NewFlagSet("set", flag.ContinueOnError)
+	fs.StringVar(&file, "f", "", "server configuration profile file to import (required)")
+	fs.StringVar(&format, "t", "", "server configuration profile format, xml or json (required)")
+	fs.StringVar(&shutdownType, "b", "", "shutdown type: Graceful, Forced, or NoReboot (optional)")
+	fs.IntVar(&opts.WaitMinutes, "w", 0, "minutes to wait for a graceful shutdown (optional)")
+	fs.StringVar(&endPowerState, "s", "", "end host power state: On or Off (optional)")
+	fs.StringVar(&share.Path, "l", "", "network share to import from, e.g. //server/share or server:/path (optional)")
+	fs.StringVar(&share.Username, "u", "", "network share username (optional)")
+	fs.StringVar(&share.Password, "p", "", "network share password (optional)")
+
+	// parse and check for basic errors
+	err = parseFlags(fs, flags)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	// validate
+	if file == "" {
+		return execResponse{}, errors.New("file name (-f) must be specified")
+	}
+	switch strings.ToLower(endPowerState) {
+	case "", "on":
+	case "off":
+		opts.EndPowerOff = true
+	default:
+		return execResponse{}, errors.New("end power state (-s) must be On or Off")
+	}
+	opts.ShutdownType = SCPShutdownType(shutdownType)
+
+	// from a share, the file is on the share
+	if share.Path != "" {
+		return rac.setSCP(ctx, nil, file, SCPFormat(format), share, opts)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return rac.setSCP(ctx, content, "", SCPFormat(format), share, opts)
+}
+
+// setSCP imports a profile, either content (put on the idrac first) or the
+// named file on share. The output has the import job's id.
+func (rac *idrac) setSCP(ctx context.Context, content []byte, file string, format SCPFormat, share NetworkShare, opts SCPImportOptions) (execResp execResponse, err error) {
+	// validate
+	err = format.validate()
+	if err != nil {
+		return execResponse{}, err
+	}
+	err = share.validate()
+	if err != nil {
+		return execResponse{}, err
+	}
+	optFlags, err := opts.flags()
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	remoteFile := file
+	if share.Path != "" {
+		if strings.ContainsAny(file, " \t\"/\\") {
+			return execResponse{}, errors.New("file name (-f) must not contain spaces, quotes, or slashes when importing from a share")
+		}
+	} else {
+		// confirm content is a profile in the stated format
+		_, contentFormat, err := ParseSCP(content)
+		if err != nil {
+			return execResponse{}, err
+		}
+		if contentFormat != format {
+			return execResponse{}, fmt.Errorf("server configuration profile is %s but format (-t) is %s", contentFormat, format)
+		}
+
+		// not binary: line endings are normalized, which doesn't change an
+		// xml or json profile (a raw CR can't appear in a json string and
+		// is normalized by xml parsers anyway)
+		remoteFile = scpRemoteFile(format)
+		err = rac.putfileContext(ctx, putfilePayload{
+			filename: remoteFile,
+			flags:    0,
+			content:  content,
+		})
+		if err != nil {
+			return execResponse{}, err
+		}
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = fmt.Sprintf("racadm set -f %s -t %s%s%s", remoteFile, format, optFlags, share.flags())
+	payload.Request.MaxOutputLen = "0x0fff"
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	// execute payload
+	execResp, err = rac.executePayloadContext(ctx, payload)
+	if err != nil {
+		return execResponse{}, err
+	}
+
+	return execResp, nil
+}
+
+// waitForSCPJob waits for the job whose id is in output
+func (rac *idrac) waitForSCPJob(ctx context.Context, output string) (Job, error) {
+	jobIDs := parseJobIDs(output)
+	if len(jobIDs) == 0 {
+		return Job{}, fmt.Errorf("no job id in output (%s)", strings.TrimSpace(output))
+	}
+
+	return rac.WaitForJob(ctx, jobIDs[0])
+}
+
+// ExportSCP exports the server configuration profile and returns it parsed,
+// keeping only the components selected by filters (all if empty; see
+// SystemConfiguration.FilterComponents)
+func (rac *idrac) ExportSCP(ctx context.Context, format SCPFormat, filters []string) (SystemConfiguration, error) {
+	err := format.validate()
+	if err != nil {
+		return SystemConfiguration{}, err
+	}
+
+	// build payload to post to drac
+	payload := execPayload{}
+	payload.Request.CommandInput = fmt.Sprintf("racadm get -f %s -t %s", scpRemoteFile(format), format)
+	payload.Request.MaxOutputLen = scpMaxOutputLen
+	payload.Request.Capability = "0x1"
+	payload.Request.UserPrivilege = 0
+
+	execResp, err := rac.executePayloadContext(withQuietOutput(ctx), payload)
+	if err != nil {
+		return SystemConfiguration{}, err
+	}
+
+	profile := extractSCP(execResp.Response.CommandOutput, format)
+	if profile == "" {
+		// some idracs only start an export job; wait for it so its outcome
+		// is logged, but the profile still isn't available here
+		if len(parseJobIDs(execResp.Response.CommandOutput)) > 0 {
+			_, err = rac.waitForSCPJob(ctx, execResp.Response.CommandOutput)
+			if err != nil {
+				return SystemConfiguration{}, err
+			}
+		}
+		return SystemConfiguration{}, errSCPNoContent
+	}
+
+	sc, _, err := ParseSCP([]byte(profile))
+	if err != nil {
+		return SystemConfiguration{}, fmt.Errorf("incomplete server configuration profile (%w)", err)
+	}
+
+	return sc.FilterComponents(filters), nil
+}
+
+// ExportSCPToShare exports the server configuration profile to file on the
+// network share and waits for the export job
+func (rac *idrac) ExportSCPToShare(ctx context.Context, file string, format SCPFormat, share NetworkShare) (Job, error) {
+	if share.Path == "" {
+		return Job{}, errors.New("network share must be specified")
+	}
+
+	execResp, err := rac.getSCP(ctx, file, format, share)
+	if err != nil {
+		return Job{}, err
+	}
+
+	return rac.waitForSCPJob(ctx, execResp.Response.CommandOutput)
+}
+
+// ImportSCP imports the profile, keeping only the components selected by
+// filters (all if empty), and waits for the import job. Host components
+// (e.g. BIOS, RAID) are applied by rebooting the server as opts specify.
+func (rac *idrac) ImportSCP(ctx context.Context, sc SystemConfiguration, format SCPFormat, filters []string, opts SCPImportOptions) (Job, error) {
+	sc = sc.FilterComponents(filters)
+	if len(sc.Components) == 0 {
+		return Job{}, errors.New("server configuration profile has no components to import")
+	}
+
+	content, err := sc.Marshal(format)
+	if err != nil {
+		return Job{}, err
+	}
+
+	execResp, err := rac.setSCP(ctx, content, "", format, NetworkShare{}, opts)
+	if err != nil {
+		return Job{}, err
+	}
+
+	return rac.waitForSCPJob(ctx, execResp.Response.CommandOutput)
+}
+
+// ImportSCPData imports the profile file content as is (unlike ImportSCP,
+// which re-encodes the parsed profile, so anything the parser doesn't model
+// such as xml comments is kept) and waits for the import job
+func (rac *idrac) ImportSCPData(ctx context.Context, content []byte, opts SCPImportOptions) (Job, error) {
+	format, err := detectSCPFormat(content)
+	if err != nil {
+		return Job{}, err
+	}
+
+	execResp, err := rac.setSCP(ctx, content, "", format, NetworkShare{}, opts)
+	if err != nil {
+		return Job{}, err
+	}
+
+	return rac.waitForSCPJob(ctx, execResp.Response.CommandOutput)
+}
+
+// ImportSCPFromShare imports the profile file on the network share and waits
+// for the import job
+func (rac *idrac) ImportSCPFromShare(ctx context.Context, file string, format SCPFormat, share NetworkShare, opts SCPImportOptions) (Job, error) {
+	if share.Path == "" {
+		return Job{}, errors.New("network share must be specified")
+	}
+
+	execResp, err := rac.setSCP(ctx, nil, file, format, share, opts)
+	if err != nil {
+		return Job{}, err
+	}
+
+	return rac.waitForSCPJob(ctx, execResp.Response.CommandOutput)
+}
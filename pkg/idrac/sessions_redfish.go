@@ -0,0 +1,121 @@
+package idrac
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// endpointRedfishSessions is the Redfish session collection. Redfish requests
+// with basic auth don't open a session, so it can be used when the session
+// limit refuses a racadm login.
+const endpointRedfishSessions = "/redfish/v1/SessionService/Sessions"
+
+// redfishSession is the part of a Redfish Session resource used to find
+// the caller's own sessions
+type redfishSession struct {
+	ODataID               string `json:"@odata.id"`
+	ID                    string `json:"Id"`
+	UserName              string `json:"UserName"`
+	ClientOriginIPAddress string `json:"ClientOriginIPAddress"`
+}
+
+// redfishDo does a Redfish request authenticated with basic auth and decodes
+// the json response (if v isn't nil)
+func (rac *idrac) redfishDo(ctx context.Context, method, path string, v any) error {
+	request, err := rac.client.newRequest(ctx, method, rac.url()+path, nil)
+	if err != nil {
+		return err
+	}
+	request.SetBasicAuth(rac.username, rac.password)
+	request.Header.Set("Accept", "application/json")
+
+	resp, err := rac.client.do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return errors.New("redfish: http status code " + strconv.Itoa(resp.StatusCode))
+	}
+	if v == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// localIP returns the local address used to reach the idrac, which is the
+// client address the idrac records for this host's sessions (unless there
+// is NAT or a proxy in between)
+func (rac *idrac) localIP() (net.IP, error) {
+	// udp "dial" only picks the route, nothing is sent
+	conn, err := net.Dial("udp", net.JoinHostPort(hostOnly(rac.hostname), "443"))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	addr, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return nil, errors.New("unable to determine local address")
+	}
+	return addr.IP, nil
+}
+
+// closeOwnSessions closes, with Redfish, the sessions of this user that were
+// opened from this host (matched on the client ip address the idrac
+// reports). It doesn't need a session, so it can run when the session limit
+// refuses a login. Sessions the idrac doesn't report an address for are
+// left alone. The number closed is returned.
+func (rac *idrac) closeOwnSessions(ctx context.Context) (int, error) {
+	ownIP, err := rac.localIP()
+	if err != nil {
+		return 0, err
+	}
+
+	collection := struct {
+		Members []redfishSession `json:"Members"`
+	}{}
+	err = rac.redfishDo(ctx, http.MethodGet, endpointRedfishSessions, &collection)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, member := range collection.Members {
+		session := redfishSession{}
+		err = rac.redfishDo(ctx, http.MethodGet, member.ODataID, &session)
+		if err != nil {
+			log.Printf("failed to read session %s (%s)", member.ODataID, err)
+			continue
+		}
+
+		ip := net.ParseIP(strings.TrimSpace(session.ClientOriginIPAddress))
+		if !strings.EqualFold(session.UserName, rac.username) || ip == nil || !ip.Equal(ownIP) {
+			continue
+		}
+
+		err = rac.redfishDo(ctx, http.MethodDelete, member.ODataID, nil)
+		if err != nil {
+			log.Printf("failed to close session %s (%s)", session.ID, err)
+			continue
+		}
+		closed++
+	}
+
+	if closed == 0 {
+		return 0, fmt.Errorf("no sessions of %s from %s found", rac.username, ownIP)
+	}
+	return closed, nil
+}
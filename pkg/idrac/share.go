@@ -0,0 +1,49 @@
+package idrac
+
+import (
+	"errors"
+	"strings"
+)
+
+// NetworkShare is a network share (CIFS //server/share or NFS server:/path)
+// for subcommands that export to or import from one (-l, -u and -p)
+type NetworkShare struct {
+	Path     string
+	Username string
+	Password string
+}
+
+// flags returns the racadm options for the share (none if Path is empty)
+func (share NetworkShare) flags() string {
+	if share.Path == "" {
+		return ""
+	}
+
+	opts := " -l " + share.Path
+	if share.Username != "" {
+		opts += " -u " + share.Username
+	}
+	if share.Password != "" {
+		opts += " -p " + share.Password
+	}
+	return opts
+}
+
+// validate checks the share for values that can't be passed to racadm
+func (share NetworkShare) validate() error {
+	if share.Path == "" {
+		if share.Username != "" || share.Password != "" {
+			return errors.New("network share (-l) must be specified with -u or -p")
+		}
+		return nil
+	}
+
+	if !strings.HasPrefix(share.Path, "//") && !strings.Contains(share.Path, ":/") {
+		return errors.New("network share (-l) must be //server/share (CIFS) or server:/path (NFS)")
+	}
+	if strings.ContainsAny(share.Path+share.Username+share.Password, " \t\"") {
+		return errors.New("network share and its credentials must not contain spaces or quotes")
+	}
+
+	return nil
+}
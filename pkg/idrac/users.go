@@ -0,0 +1,299 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// UserPrivilege is a user's privilege bitmask
+type UserPrivilege uint32
+
+// common privilege bitmasks
+const (
+	UserPrivilegeNone          = UserPrivilege(0x0)
+	UserPrivilegeReadOnly      = UserPrivilege(0x1)
+	UserPrivilegeOperator      = UserPrivilege(0x1f3)
+	UserPrivilegeAdministrator = UserPrivilege(0x1ff)
+)
+
+// String returns the privilege in the hex form racadm uses
+func (priv UserPrivilege) String() string {
+	return fmt.Sprintf("0x%08x", uint32(priv))
+}
+
+// user slots; slot 1 is reserved (anonymous) and can't be changed
+const (
+	userFirstSlot = 2
+	userLastSlot  = 16
+)
+
+// legacy (cfgUserAdmin) object names
+const (
+	cfgUserAdminGroup     = "cfgUserAdmin"
+	cfgUserAdminUserName  = "cfgUserAdminUserName"
+	cfgUserAdminPassword  = "cfgUserAdminPassword"
+	cfgUserAdminEnable    = "cfgUserAdminEnable"
+	cfgUserAdminPrivilege = "cfgUserAdminPrivilege"
+)
+
+var (
+	errInvalidUserSlot = fmt.Errorf("user index must be between %d and %d", userFirstSlot, userLastSlot)
+
+	// ErrCredentialVerification is wrapped by errors returned when a
+	// credential was written but logging in with it failed
+	ErrCredentialVerification = errors.New("login with new credential failed")
+)
+
+// User is an idrac local user account
+type User struct {
+	Index     int           `json:"index"`
+	UserName  string        `json:"username"`
+	Enabled   bool          `json:"enabled"`
+	Privilege UserPrivilege `json:"privilege"`
+}
+
+// userAttributeKey returns the iDRAC8/9 attribute key for a user's property
+// (e.g. iDRAC.Users.2.UserName)
+func userAttributeKey(index int, property string) string {
+	return fmt.Sprintf("iDRAC.Users.%d.%s", index, property)
+}
+
+// GetUser returns the user in slot index
+func (rac *idrac) GetUser(ctx context.Context, index int) (User, error) {
+	if index < 1 || index > userLastSlot {
+		return User{}, errInvalidUserSlot
+	}
+
+	user := User{Index: index}
+	values := map[string]string{}
+
+	if rac.usesAttributes(ctx) {
+		tree, err := rac.Get(ctx, fmt.Sprintf("iDRAC.Users.%d", index))
+		if err != nil {
+			return User{}, err
+		}
+		for _, property := range []string{"UserName", "Enable", "Privilege"} {
+			attr, _ := tree.Attribute(property)
+			values[property] = attr.Value
+		}
+	} else {
+		tree, err := rac.GetConfig(ctx, cfgUserAdminGroup, index)
+		if err != nil {
+			return User{}, err
+		}
+		for property, object := range map[string]string{
+			"UserName":  cfgUserAdminUserName,
+			"Enable":    cfgUserAdminEnable,
+			"Privilege": cfgUserAdminPrivilege,
+		} {
+			attr, _ := tree.Attribute(object)
+			values[property] = attr.Value
+		}
+	}
+
+	user.UserName = values["UserName"]
+	user.Enabled = values["Enable"] == "1" || strings.EqualFold(values["Enable"], "enabled")
+	if values["Privilege"] != "" {
+		priv, err := strconv.ParseUint(values["Privilege"], 0, 32)
+		if err != nil {
+			return User{}, fmt.Errorf("invalid privilege (%s) for user %d", values["Privilege"], index)
+		}
+		user.Privilege = UserPrivilege(priv)
+	}
+
+	return user, nil
+}
+
+// Users returns all user slots (including empty ones, which have no
+// UserName), except the reserved slot 1
+func (rac *idrac) Users(ctx context.Context) ([]User, error) {
+	users := []User{}
+	for index := userFirstSlot; index <= userLastSlot; index++ {
+		user, err := rac.GetUser(ctx, index)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// FindUser returns the user with username
+func (rac *idrac) FindUser(ctx context.Context, username string) (User, error) {
+	users, err := rac.Users(ctx)
+	if err != nil {
+		return User{}, err
+	}
+
+	for _, user := range users {
+		if user.UserName == username {
+			return user, nil
+		}
+	}
+
+	return User{}, fmt.Errorf("user %s not found", username)
+}
+
+// FreeUserSlot returns the index of the first slot without a user
+func (rac *idrac) FreeUserSlot(ctx context.Context) (int, error) {
+	users, err := rac.Users(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, user := range users {
+		if user.UserName == "" {
+			return user.Index, nil
+		}
+	}
+
+	return 0, errors.New("no free user slots")
+}
+
+// setUserProperty writes one property of a user, using set (iDRAC8/9) or
+// config (iDRAC6/7). property is the attribute name; it is mapped to the
+// legacy object name when needed.
+func (rac *idrac) setUserProperty(ctx context.Context, index int, property, value string) error {
+	if index < userFirstSlot || index > userLastSlot {
+		return errInvalidUserSlot
+	}
+
+	if rac.usesAttributes(ctx) {
+		_, err := rac.Set(ctx, userAttributeKey(index, property), value)
+		return err
+	}
+
+	object := map[string]string{
+		"UserName":  cfgUserAdminUserName,
+		"Password":  cfgUserAdminPassword,
+		"Enable":    cfgUserAdminEnable,
+		"Privilege": cfgUserAdminPrivilege,
+	}[property]
+	if object == "" {
+		return fmt.Errorf("unknown user property (%s)", property)
+	}
+
+	// legacy enable is 1/0
+	switch value {
+	case "Enabled":
+		value = "1"
+	case "Disabled":
+		value = "0"
+	}
+
+	return rac.Config(ctx, cfgUserAdminGroup, object, index, value)
+}
+
+// CreateUser creates an enabled user in slot index (use FreeUserSlot to
+// find one) and then verifies the new credential by logging in with it
+func (rac *idrac) CreateUser(ctx context.Context, index int, username, password string, privilege UserPrivilege) error {
+	if username == "" || password == "" {
+		return errors.New("username and password must be specified")
+	}
+
+	// don't overwrite an existing user
+	existing, err := rac.GetUser(ctx, index)
+	if err != nil {
+		return err
+	}
+	if existing.UserName != "" {
+		return fmt.Errorf("user slot %d is in use (%s)", index, existing.UserName)
+	}
+
+	// username, password, privilege, then enable (so a partially created
+	// user is never enabled)
+	for _, p := range []struct {
+		property string
+		value    string
+	}{
+		{"UserName", username},
+		{"Password", password},
+		{"Privilege", privilege.String()},
+		{"Enable", "Enabled"},
+	} {
+		err = rac.setUserProperty(ctx, index, p.property, p.value)
+		if err != nil {
+			return fmt.Errorf("failed to set user %d %s (%w)", index, strings.ToLower(p.property), err)
+		}
+	}
+
+	err = rac.verifyCredential(username, password)
+	if err != nil {
+		return fmt.Errorf("user %s created but %w (%s)", username, ErrCredentialVerification, err)
+	}
+
+	return nil
+}
+
+// SetUserEnabled enables or disables the user in slot index
+func (rac *idrac) SetUserEnabled(ctx context.Context, index int, enabled bool) error {
+	value := "Disabled"
+	if enabled {
+		value = "Enabled"
+	}
+
+	return rac.setUserProperty(ctx, index, "Enable", value)
+}
+
+// SetUserPrivilege sets the privilege of the user in slot index
+func (rac *idrac) SetUserPrivilege(ctx context.Context, index int, privilege UserPrivilege) error {
+	return rac.setUserProperty(ctx, index, "Privilege", privilege.String())
+}
+
+// SetUserPassword sets the password of the user in slot index without
+// verifying it (e.g. to roll back a change)
+func (rac *idrac) SetUserPassword(ctx context.Context, index int, password string) error {
+	if password == "" {
+		return errors.New("password must be specified")
+	}
+
+	return rac.setUserProperty(ctx, index, "Password", password)
+}
+
+// ChangeUserPassword changes the password of the user in slot index and
+// then verifies the new credential by logging in with it
+func (rac *idrac) ChangeUserPassword(ctx context.Context, index int, password string) error {
+	if password == "" {
+		return errors.New("password must be specified")
+	}
+
+	user, err := rac.GetUser(ctx, index)
+	if err != nil {
+		return err
+	}
+	if user.UserName == "" {
+		return fmt.Errorf("user slot %d is empty", index)
+	}
+
+	err = rac.SetUserPassword(ctx, index, password)
+	if err != nil {
+		return fmt.Errorf("failed to change password of user %s (%w)", user.UserName, err)
+	}
+
+	err = rac.verifyCredential(user.UserName, password)
+	if err != nil {
+		return fmt.Errorf("password of user %s changed but %w (%s)", user.UserName, ErrCredentialVerification, err)
+	}
+
+	// keep this client working if its own password was changed
+	if user.UserName == rac.username {
+		rac.password = password
+	}
+
+	return nil
+}
+
+// ChangeOwnPassword changes the password of the logged in user (e.g. when
+// Login reports default credentials are in use) and verifies it
+func (rac *idrac) ChangeOwnPassword(ctx context.Context, password string) error {
+	user, err := rac.FindUser(ctx, rac.username)
+	if err != nil {
+		return err
+	}
+
+	return rac.ChangeUserPassword(ctx, user.Index, password)
+}